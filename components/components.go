@@ -6,8 +6,6 @@
 package components
 
 import (
-	"maps"
-
 	twmerge "github.com/Oudwins/tailwind-merge-go"
 	"github.com/canpacis/pacis/html"
 )
@@ -129,13 +127,12 @@ func (*AsChildProperty) Apply(el *html.Element) {
 	if !ok {
 		panic("Non element node is passed to the element with AsChild propery")
 	}
+	// The child is shared when it is frozen, it is copied before it is modified
+	child = child.Mutable()
 
-	attrs := map[string]string{}
-	maps.Copy(attrs, child.GetAttributes())
-	maps.Copy(attrs, el.GetAttributes())
-	class := child.GetAttribute("class")
-	child.SetAttributes(attrs)
-	child.AddClass(class)
+	// Attributes of the element override the ones of the child in a stable order,
+	// class lists are merged with the classes of the element first
+	child.MergeAttributes(el)
 	*el = *child
 }
 
@@ -8,6 +8,7 @@ require (
 	github.com/canpacis/http-payload v0.3.1
 	github.com/nicksnyder/go-i18n/v2 v2.6.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/net v0.38.0
 	golang.org/x/text v0.23.0
 )
 
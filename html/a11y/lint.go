@@ -0,0 +1,168 @@
+// Package a11y finds common accessibility problems in rendered node trees, such as images
+// without alternative text, buttons without accessible names, aria-labelledby references
+// to missing ids and duplicate ids.
+//
+// Example usage:
+//
+//	issues, err := a11y.LintMarkup(strings.NewReader(markup))
+//	if err != nil { ... }
+//	for _, issue := range issues {
+//		log.Println(issue)
+//	}
+//
+// Tests can assert that a node has no issues with htmltest:
+//
+//	htmltest.Render(t, Page()).Accessible()
+package a11y
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// Rules that an Issue reports a violation of.
+const (
+	// An <img> element has no alt attribute, use an empty alt for decorative images.
+	RuleImageAlt = "image-alt"
+	// A button has no text content or label, e.g. a button with only an icon.
+	RuleButtonName = "button-name"
+	// An aria-labelledby or aria-describedby attribute refers to an id that does not exist.
+	RuleARIAReference = "aria-reference"
+	// More than one element has the same id.
+	RuleDuplicateID = "duplicate-id"
+)
+
+// Issue is an accessibility problem of an element.
+type Issue struct {
+	Rule string
+	// Path of the element from the root, e.g. "body > main > button.icon:nth-of-type(2)"
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	return i.Path + ": " + i.Message + " (" + i.Rule + ")"
+}
+
+// Lint walks the node trees and reports the issues of their elements. Components are not rendered, lint rendered markup with LintMarkup to include them.
+func Lint(nodes ...html.Node) []Issue {
+	l := &linter{root: html.El("a11y-root"), ids: map[string]string{}, issues: []Issue{}}
+	for _, node := range nodes {
+		l.root.AppendNode(node)
+	}
+	l.namer = NewNamer(l.root)
+	l.walk(l.root.GetNodes(), "", state{})
+	// References are checked after the whole tree is walked since they may point forward
+	l.references(l.root.GetNodes(), "")
+	return l.issues
+}
+
+// LintMarkup parses the markup and reports its issues, see Lint.
+func LintMarkup(r io.Reader) ([]Issue, error) {
+	nodes, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return Lint(nodes...), nil
+}
+
+type linter struct {
+	root *html.Element
+	// Paths of the elements by their ids
+	ids    map[string]string
+	namer  *Namer
+	issues []Issue
+}
+
+func (l *linter) report(rule, path, message string) {
+	l.issues = append(l.issues, Issue{Rule: rule, Path: path, Message: message})
+}
+
+// state is the state of the walk at an element.
+type state struct {
+	// Whether the element is excluded from the accessibility tree
+	hidden bool
+	// Whether the element is within a <template>, the contents of templates are copied
+	// into the document (e.g. by x-for) and may repeat their ids
+	template bool
+	// Whether the element is within <svg> or <math>, foreign content is only checked for ids
+	foreign bool
+}
+
+func (l *linter) walk(nodes []html.Node, parent string, s state) {
+	children := elements(nodes)
+	for _, el := range children {
+		path := html.PathSegment(el, children)
+		if len(parent) > 0 {
+			path = parent + " > " + path
+		}
+		s := s
+		s.hidden = s.hidden || Hidden(el)
+		l.element(el, path, s)
+
+		s.template = s.template || el.Tag() == "template"
+		s.foreign = s.foreign || el.Tag() == "svg" || el.Tag() == "math"
+		l.walk(el.GetNodes(), path, s)
+	}
+}
+
+func (l *linter) element(el *html.Element, path string, s state) {
+	if id := el.GetAttribute("id"); len(id) > 0 && !s.template {
+		if first, ok := l.ids[id]; ok {
+			l.report(RuleDuplicateID, path, "id "+strconv.Quote(id)+" is already used by "+first)
+		} else {
+			l.ids[id] = path
+		}
+	}
+	if s.hidden || s.foreign {
+		return
+	}
+
+	if el.Tag() == "img" && !el.HasAttribute("alt") && len(l.namer.Name(el)) == 0 {
+		switch Role(el) {
+		case "presentation", "none":
+		default:
+			l.report(RuleImageAlt, path, "image has no alt attribute, describe it or use an empty alt if it is decorative")
+		}
+	}
+	if Role(el) == "button" && len(l.namer.Name(el)) == 0 {
+		l.report(RuleButtonName, path, "button has no accessible name, add text content or an aria-label")
+	}
+}
+
+// references reports the aria references to the ids that no element has.
+func (l *linter) references(nodes []html.Node, parent string) {
+	children := elements(nodes)
+	for _, el := range children {
+		path := html.PathSegment(el, children)
+		if len(parent) > 0 {
+			path = parent + " > " + path
+		}
+		for _, attr := range []string{"aria-labelledby", "aria-describedby"} {
+			for _, id := range strings.Fields(el.GetAttribute(attr)) {
+				// Unlike the duplicate check, the ids within templates count
+				if _, ok := l.namer.ids[id]; !ok {
+					l.report(RuleARIAReference, path, attr+" refers to the missing id "+strconv.Quote(id))
+				}
+			}
+		}
+		l.references(el.GetNodes(), path)
+	}
+}
+
+// elements returns the element children among the nodes, flattening any fragments in between.
+func elements(nodes []html.Node) []*html.Element {
+	children := []*html.Element{}
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.Element:
+			children = append(children, node)
+		case html.Frag:
+			children = append(children, elements(node)...)
+		}
+	}
+	return children
+}
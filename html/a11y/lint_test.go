@@ -0,0 +1,44 @@
+package a11y_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/a11y"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	icon := html.El("svg", html.Aria("hidden", "true"), html.El("path"))
+	page := html.Main(
+		html.Img(html.Src("/logo.png")),
+		html.Img(html.Src("/divider.png"), html.Alt("")),
+		html.Img(html.Src("/chart.png"), html.Aria("label", "Sales chart")),
+		html.Nav(
+			html.Button(html.Class("icon"), icon),
+			html.Button(html.Class("icon"), html.Aria("label", "Close"), icon),
+			html.Button(html.Aria("labelledby", "missing"), html.Text("Open")),
+			html.Button(html.Aria("labelledby", "save-label"), icon),
+		),
+		html.Span(html.ID("save-label"), html.Text("Save")),
+		html.Div(html.Hidden(""), html.Button()),
+		html.Template(html.Div(html.ID("row"))),
+		html.Template(html.Div(html.ID("row"))),
+		html.Section(html.ID("save-label")),
+	)
+
+	assert.Equal([]a11y.Issue{
+		{Rule: a11y.RuleImageAlt, Path: "main > img:nth-of-type(1)", Message: "image has no alt attribute, describe it or use an empty alt if it is decorative"},
+		{Rule: a11y.RuleButtonName, Path: "main > nav > button.icon:nth-of-type(1)", Message: "button has no accessible name, add text content or an aria-label"},
+		{Rule: a11y.RuleDuplicateID, Path: "main > section#save-label", Message: `id "save-label" is already used by main > span#save-label`},
+		{Rule: a11y.RuleARIAReference, Path: "main > nav > button:nth-of-type(3)", Message: `aria-labelledby refers to the missing id "missing"`},
+	}, a11y.Lint(page))
+
+	issues, err := a11y.LintMarkup(strings.NewReader(`<!DOCTYPE html><html lang="en"><head><title>Home</title></head><body><input type="submit" value="Send"><input type="image" src="/go.png"></body></html>`))
+	assert.NoError(err)
+	assert.Len(issues, 1)
+	assert.Equal("html > body > input:nth-of-type(2): button has no accessible name, add text content or an aria-label (button-name)", issues[0].String())
+}
@@ -0,0 +1,245 @@
+package a11y
+
+import (
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// Hidden reports whether the element and its subtree are excluded from the accessibility tree.
+func Hidden(el *html.Element) bool {
+	if el.HasAttribute("hidden") || el.GetAttribute("aria-hidden") == "true" {
+		return true
+	}
+	switch el.Tag() {
+	case "head", "script", "style", "template":
+		return true
+	}
+	return false
+}
+
+// Role returns the explicit or the implicit ARIA role of the element, or an empty string
+// if it has none, see https://www.w3.org/TR/html-aria/#docconformance
+func Role(el *html.Element) string {
+	if role := strings.Fields(el.GetAttribute("role")); len(role) > 0 {
+		return role[0]
+	}
+
+	switch el.Tag() {
+	case "a", "area":
+		if el.HasAttribute("href") {
+			return "link"
+		}
+	case "article":
+		return "article"
+	case "aside":
+		return "complementary"
+	case "button":
+		return "button"
+	case "details":
+		return "group"
+	case "dialog":
+		return "dialog"
+	case "fieldset":
+		return "group"
+	case "footer":
+		return "contentinfo"
+	case "form":
+		return "form"
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return "heading"
+	case "header":
+		return "banner"
+	case "hr":
+		return "separator"
+	case "img":
+		if el.HasAttribute("alt") && len(el.GetAttribute("alt")) == 0 {
+			return "presentation"
+		}
+		return "img"
+	case "input":
+		switch strings.ToLower(el.GetAttribute("type")) {
+		case "button", "image", "reset", "submit":
+			return "button"
+		case "checkbox":
+			return "checkbox"
+		case "radio":
+			return "radio"
+		case "range":
+			return "slider"
+		case "number":
+			return "spinbutton"
+		case "search":
+			return "searchbox"
+		case "", "email", "tel", "text", "url":
+			if el.HasAttribute("list") {
+				return "combobox"
+			}
+			return "textbox"
+		}
+	case "li":
+		return "listitem"
+	case "main":
+		return "main"
+	case "menu", "ol", "ul":
+		return "list"
+	case "nav":
+		return "navigation"
+	case "option":
+		return "option"
+	case "progress":
+		return "progressbar"
+	case "section":
+		if el.HasAttribute("aria-label") || el.HasAttribute("aria-labelledby") {
+			return "region"
+		}
+	case "select":
+		if el.HasAttribute("multiple") {
+			return "listbox"
+		}
+		return "combobox"
+	case "table":
+		return "table"
+	case "tbody", "tfoot", "thead":
+		return "rowgroup"
+	case "td":
+		return "cell"
+	case "textarea":
+		return "textbox"
+	case "th":
+		return "columnheader"
+	case "tr":
+		return "row"
+	}
+	return ""
+}
+
+// Name computes a simplified accessible name of the element, the labels and the targets
+// of aria-labelledby are looked up within root, see https://www.w3.org/TR/accname-1.2/
+// Use a Namer to compute the names of many elements within the same root.
+func Name(el *html.Element, root *html.Element) string {
+	return NewNamer(root).Name(el)
+}
+
+// Namer computes the accessible names of the elements within a root, the elements with ids
+// and the labels of the root are collected once when it is created.
+type Namer struct {
+	ids    map[string]*html.Element
+	labels []*html.Element
+}
+
+// NewNamer creates a Namer for the elements within root.
+func NewNamer(root *html.Element) *Namer {
+	n := &Namer{ids: map[string]*html.Element{}, labels: []*html.Element{}}
+	html.Walk(root, func(node html.Node) error {
+		el, ok := node.(*html.Element)
+		if !ok {
+			return nil
+		}
+		if id := el.GetAttribute("id"); len(id) > 0 {
+			if _, ok := n.ids[id]; !ok {
+				n.ids[id] = el
+			}
+		}
+		if el.Tag() == "label" {
+			n.labels = append(n.labels, el)
+		}
+		return nil
+	})
+	return n
+}
+
+// Name computes the accessible name of the element, see the Name function.
+func (n *Namer) Name(el *html.Element) string {
+	if labelledby := strings.Fields(el.GetAttribute("aria-labelledby")); len(labelledby) > 0 {
+		names := []string{}
+		for _, id := range labelledby {
+			if target, ok := n.ids[id]; ok {
+				names = append(names, contentname(target))
+			}
+		}
+		if name := normalize(strings.Join(names, " ")); len(name) > 0 {
+			return name
+		}
+	}
+	if label := normalize(el.GetAttribute("aria-label")); len(label) > 0 {
+		return label
+	}
+
+	switch el.Tag() {
+	case "img", "area":
+		return normalize(el.GetAttribute("alt"))
+	case "input", "select", "textarea":
+		if el.Tag() == "input" {
+			switch strings.ToLower(el.GetAttribute("type")) {
+			case "button", "reset", "submit":
+				return normalize(el.GetAttribute("value"))
+			case "image":
+				return normalize(el.GetAttribute("alt"))
+			}
+		}
+		if name := n.labelof(el); len(name) > 0 {
+			return name
+		}
+		return normalize(el.GetAttribute("title"))
+	}
+
+	switch Role(el) {
+	case "button", "cell", "columnheader", "heading", "link", "listitem", "option", "row", "tab", "menuitem", "checkbox", "radio", "switch":
+		if name := contentname(el); len(name) > 0 {
+			return name
+		}
+	}
+	return normalize(el.GetAttribute("title"))
+}
+
+// labelof returns the text of the <label> elements associated with the form control.
+func (n *Namer) labelof(control *html.Element) string {
+	id := control.GetAttribute("id")
+	names := []string{}
+	for _, label := range n.labels {
+		if len(id) > 0 && label.GetAttribute("for") == id {
+			names = append(names, contentname(label))
+			continue
+		}
+		html.Walk(label, func(node html.Node) error {
+			if node == control {
+				names = append(names, contentname(label))
+				return html.SkipAll
+			}
+			return nil
+		})
+	}
+	return normalize(strings.Join(names, " "))
+}
+
+// contentname computes the name of an element from its content, the alt text of images and
+// the labels of nested elements are included while hidden elements are skipped.
+func contentname(el *html.Element) string {
+	b := new(strings.Builder)
+	html.Walk(el, func(node html.Node) error {
+		switch node := node.(type) {
+		case *html.Element:
+			if Hidden(node) {
+				return html.SkipChildren
+			}
+			if node != el {
+				if label := node.GetAttribute("aria-label"); len(label) > 0 {
+					b.WriteString(" " + label + " ")
+					return html.SkipChildren
+				}
+			}
+			if node.Tag() == "img" {
+				b.WriteString(" " + node.GetAttribute("alt") + " ")
+			}
+		case html.Text:
+			b.WriteString(string(node))
+		}
+		return nil
+	})
+	return normalize(b.String())
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
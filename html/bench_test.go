@@ -0,0 +1,134 @@
+package html_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// The benchmarks are run with:
+//
+//	go test -run '^$' -bench . -benchmem ./html ./server
+//
+// Allocations per operation before the render pipeline was redesigned (fmt based tags,
+// unused pools) and when they were last updated:
+//
+//	                 before  after
+//	BuildPage           947    156
+//	RenderPage         1555     30
+//	BuildRenderPage    2503    186
+//	RenderComponent    2492    471
+//	PageHandler         606    153 (server)
+
+// benchpage builds a typical page with a navigation, a list of cards and a form.
+func benchpage() html.Node {
+	cards := make([]int, 20)
+	for i := range cards {
+		cards[i] = i
+	}
+
+	return html.Fragment(
+		html.Doctype,
+		html.Html(
+			html.Lang("en"),
+
+			html.Head(
+				html.Meta(html.Charset("UTF-8")),
+				html.Title(html.Text("Benchmark")),
+				html.Link(html.Rel("stylesheet"), html.Href("/main.css")),
+			),
+			html.Body(
+				html.Class("min-h-screen bg-background"),
+
+				html.Nav(
+					html.Ul(
+						html.Li(html.A(html.Href("/"), html.Text("Home"))),
+						html.Li(html.A(html.Href("/docs"), html.Text("Docs"))),
+						html.Li(html.A(html.Href("/blog"), html.Text("Blog"))),
+					),
+				),
+				html.Main(
+					html.Map(cards, func(i int) html.Node {
+						return html.Div(
+							html.Class("card"),
+							html.Data("slot", "card"),
+
+							html.H2(html.Text("Card "+strconv.Itoa(i))),
+							html.P(html.Text("Lorem ipsum dolor sit amet, consectetur adipiscing elit.")),
+							html.Img(html.Src("/image.png"), html.Alt("Image")),
+						)
+					}),
+				),
+				html.Form(
+					html.Input(html.Type("email"), html.Name("email"), html.Placeholder("Email")),
+					html.Button(html.Type("submit"), html.Text("Subscribe")),
+				),
+			),
+		),
+	)
+}
+
+func benchrender(b *testing.B, node html.Node, ctx context.Context) {
+	cw := html.NewChunkWriter()
+	if err := node.Render(cw); err != nil {
+		b.Fatal(err)
+	}
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, ctx, io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Building the tree of a page and releasing it.
+func BenchmarkBuildPage(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		benchpage().Release()
+	}
+}
+
+// Rendering a page that is built once, like the static renderer of the server does.
+func BenchmarkRenderPage(b *testing.B) {
+	page := benchpage()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		benchrender(b, page, ctx)
+	}
+}
+
+// Building, rendering and releasing a page on every iteration, like a request scoped tree.
+func BenchmarkBuildRenderPage(b *testing.B) {
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		page := benchpage()
+		benchrender(b, page, ctx)
+		page.Release()
+	}
+}
+
+// Rendering a component, which builds and renders its tree on every render.
+func BenchmarkRenderComponent(b *testing.B) {
+	component := html.Component(func(ctx context.Context) html.Node { return benchpage() })
+	ctx := context.Background()
+
+	cw := html.NewChunkWriter()
+	component.Render(cw)
+	chunks := cw.Chunks()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, chunk := range chunks {
+			if err := html.Render(chunk, ctx, io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
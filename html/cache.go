@@ -0,0 +1,270 @@
+package html
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type cacheentry struct {
+	key     string
+	value   []byte
+	effects []effect
+	tags    []string
+	expires time.Time
+}
+
+func (e *cacheentry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// Cache is a bounded, in-memory least recently used cache of rendered component output.
+// It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	tags    map[string]map[string]struct{}
+	// Returns the current time the entries expire by, see WithClock
+	now func() time.Time
+}
+
+// NewCache creates a new Cache that holds at most size entries.
+func NewCache(size int) *Cache {
+	if size <= 0 {
+		size = 1
+	}
+	return &Cache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		tags:    make(map[string]map[string]struct{}),
+		now:     time.Now,
+	}
+}
+
+// Sets the function that returns the current time the entries expire by and returns the
+// cache back, e.g. to control the time in tests. Defaults to time.Now.
+func (c *Cache) WithClock(now func() time.Time) *Cache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+	return c
+}
+
+// DefaultCache is the cache used by the nodes created with Cached unless another one is set with WithCache.
+var DefaultCache = NewCache(1024)
+
+// Get returns the cached value for the given key, if it exists and has not expired.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	entry, ok := c.get(key)
+	if !ok {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *Cache) get(key string) (*cacheentry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheentry)
+	if entry.expired(c.now()) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+// Set stores the value for the given key with the given tags. A ttl of zero means the
+// value never expires. The least recently used entry is evicted if the cache is full.
+func (c *Cache) Set(key string, value []byte, ttl time.Duration, tags ...string) {
+	c.set(&cacheentry{key: key, value: value, tags: tags}, ttl)
+}
+
+func (c *Cache) set(entry *cacheentry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, tags := entry.key, entry.tags
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	if ttl > 0 {
+		entry.expires = c.now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Invalidate removes the entries with the given keys.
+func (c *Cache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// InvalidateTag removes every entry that was stored with any of the given tags.
+func (c *Cache) InvalidateTag(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if elem, ok := c.entries[key]; ok {
+				c.remove(elem)
+			}
+		}
+	}
+}
+
+// Clear removes every entry from the cache.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.tags = make(map[string]map[string]struct{})
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove deletes the entry from the cache, the lock must be held by the caller.
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cacheentry)
+	delete(c.entries, entry.key)
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+/*
+CachedNode renders a component once per key and serves the rendered bytes from a cache
+for subsequent renders until the entry expires or is invalidated.
+
+Since the component is not run on cache hits, side effects of the component (setting
+cookies, redirects, async chunks) only happen when the entry is rendered. Once and head
+portal nodes are the exception, their keys and hoisted output are stored with the entry
+and replayed in every response it is written to. Once nodes that are rendered in place
+and whose key the response has already seen when the entry is rendered are not part of
+the entry.
+
+Usage:
+
+	html.Cached(
+		func(ctx context.Context) string { return "nav:" + locale(ctx) },
+		time.Minute*5,
+		html.Component(Navigation),
+	).WithTags("nav")
+
+	// Later, after the navigation is updated
+	html.DefaultCache.InvalidateTag("nav")
+*/
+type CachedNode struct {
+	key   func(context.Context) string
+	ttl   time.Duration
+	comp  Component
+	tags  []string
+	cache *Cache
+}
+
+// Implements the Item interface.
+func (*CachedNode) Item() {}
+
+// Implements the Node interface.
+func (*CachedNode) Release() {
+	// no-op
+}
+
+// Associates the given tags with the entries of the node and returns it back.
+func (n *CachedNode) WithTags(tags ...string) *CachedNode {
+	n.tags = append(n.tags, tags...)
+	return n
+}
+
+// Sets the cache the node stores its entries in and returns it back.
+func (n *CachedNode) WithCache(cache *Cache) *CachedNode {
+	n.cache = cache
+	return n
+}
+
+// Implements the Node interface.
+func (n *CachedNode) Render(w ChunkWriter) error {
+	// The component is rendered into a single dynamic chunk that carries the formatting state
+	inner := newcw(formatof(w))
+	if err := n.comp.Render(inner); err != nil {
+		return err
+	}
+	chunks := inner.Chunks()
+
+	w.Write(DynamicChunk(func(ctx context.Context, out io.Writer) error {
+		key := n.key(ctx)
+		if len(key) == 0 {
+			return renderchunks(chunks, ctx, out)
+		}
+		if entry, ok := n.cache.get(key); ok {
+			return n.write(ctx, entry, out)
+		}
+
+		// The nonce and the response scope of this request must not end up in the cache
+		rctx, scope := recordscope(withplaceholdernonce(ctx))
+		buf := new(bytes.Buffer)
+		if err := renderchunks(chunks, rctx, buf); err != nil {
+			return err
+		}
+		entry := &cacheentry{key: key, value: bytes.Clone(buf.Bytes()), effects: scope.effects, tags: n.tags}
+		n.cache.set(entry, n.ttl)
+		return n.write(ctx, entry, out)
+	}))
+	return nil
+}
+
+// write writes the cached entry to out in the response of ctx.
+func (n *CachedNode) write(ctx context.Context, entry *cacheentry, out io.Writer) error {
+	if err := replay(ctx, entry.effects, out); err != nil {
+		return err
+	}
+	_, err := out.Write(resolveoutlets(ctx, resolvenonce(ctx, entry.value)))
+	return err
+}
+
+// Cached creates a node that caches the rendered output of comp in DefaultCache under the
+// key returned by keyFn for the given ttl. A ttl of zero means the entry never expires and
+// an empty key skips the cache for that render. Keys are shared by every cached node that
+// uses the same cache, so they should be unique to the component.
+func Cached(keyFn func(context.Context) string, ttl time.Duration, comp Component) *CachedNode {
+	return &CachedNode{key: keyFn, ttl: ttl, comp: comp, cache: DefaultCache}
+}
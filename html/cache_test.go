@@ -0,0 +1,147 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+type localekey struct{}
+
+func TestCached(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := html.NewCache(2)
+	renders := 0
+	node := html.Nav(
+		html.Cached(
+			func(ctx context.Context) string { return "nav:" + ctx.Value(localekey{}).(string) },
+			0,
+			html.Component(func(ctx context.Context) html.Node {
+				renders++
+				return html.Text(fmt.Sprintf("%s %d", ctx.Value(localekey{}), renders))
+			}),
+		).WithCache(cache).WithTags("nav"),
+	)
+	cw := html.NewChunkWriter()
+	assert.NoError(node.Render(cw))
+	chunks := cw.Chunks()
+
+	render := func(locale string) string {
+		ctx := context.WithValue(context.Background(), localekey{}, locale)
+		out := new(bytes.Buffer)
+		for _, chunk := range chunks {
+			assert.NoError(html.Render(chunk, ctx, out))
+		}
+		return out.String()
+	}
+
+	assert.Equal("<nav>en 1</nav>", render("en"))
+	assert.Equal("<nav>en 1</nav>", render("en"))
+	assert.Equal("<nav>tr 2</nav>", render("tr"))
+	assert.Equal(2, renders)
+
+	cache.Invalidate("nav:en")
+	assert.Equal("<nav>en 3</nav>", render("en"))
+	assert.Equal("<nav>tr 2</nav>", render("tr"))
+
+	cache.InvalidateTag("nav")
+	assert.Equal(0, cache.Len())
+	assert.Equal("<nav>tr 4</nav>", render("tr"))
+}
+
+func TestCacheEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := html.NewCache(2).WithClock(func() time.Time { return now })
+	cache.Set("a", []byte("a"), 0)
+	cache.Set("b", []byte("b"), 0)
+	_, ok := cache.Get("a")
+	assert.True(ok)
+
+	cache.Set("c", []byte("c"), 0)
+	_, ok = cache.Get("b")
+	assert.False(ok, "least recently used entry should be evicted")
+	_, ok = cache.Get("a")
+	assert.True(ok)
+
+	cache.Set("d", []byte("d"), time.Minute)
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("d")
+	assert.True(ok)
+	now = now.Add(time.Second)
+	_, ok = cache.Get("d")
+	assert.False(ok, "expired entry should not be served")
+}
+
+func TestCachedOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	renders := 0
+	node := html.Html(
+		html.Head(),
+		html.Body(
+			html.Cached(func(context.Context) string { return "card" }, 0, html.Component(func(context.Context) html.Node {
+				renders++
+				return html.Fragment(
+					html.Once("card-style", html.Style(html.RawUnsafe(".card{}"))).Hoist(html.HoistHead),
+					html.Once("card-divider", html.Hr()),
+					html.Div(html.Class("card")),
+				)
+			})).WithCache(html.NewCache(1)),
+			html.Once("card-divider", html.Hr()),
+		),
+	)
+
+	// Cache hits replay the hoisted nodes and the once keys of the entry
+	for range 2 {
+		ctx := html.WithResponseScope(context.Background())
+		rendered, err := renderContext(ctx, node)
+		assert.NoError(err)
+		assert.Equal(
+			`<html><head><style>.card{}</style></head><body><hr><div class="card"></div></body></html>`,
+			string(html.ResolveHoisted(ctx, []byte(rendered))),
+		)
+	}
+	assert.Equal(1, renders)
+
+	// Without a scope the hoisted nodes are rendered in place
+	rendered, err := renderContext(context.Background(), node)
+	assert.NoError(err)
+	assert.Equal(`<html><head></head><body><style>.card{}</style><hr><div class="card"></div><hr></body></html>`, rendered)
+}
+
+func TestCachedOnceSeen(t *testing.T) {
+	assert := assert.New(t)
+
+	divider := html.Once("divider", html.Hr())
+	style := html.Once("card-style", html.Style(html.RawUnsafe(".card{}"))).Hoist(html.HoistHead)
+	cached := html.Cached(func(context.Context) string { return "card" }, 0, html.Component(func(context.Context) html.Node {
+		return html.Fragment(style, divider, html.Div(html.Class("card")))
+	})).WithCache(html.NewCache(1))
+
+	// The cached subtree repeats the keys that are rendered before it
+	page := html.Html(html.Head(), html.Body(style, divider, cached))
+	ctx := html.WithResponseScope(context.Background())
+	rendered, err := renderContext(ctx, page)
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><style>.card{}</style></head><body><hr><div class="card"></div></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Responses that did not render the keys before still get the hoisted nodes of the entry
+	ctx = html.WithResponseScope(context.Background())
+	rendered, err = renderContext(ctx, html.Html(html.Head(), html.Body(cached)))
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><style>.card{}</style></head><body><div class="card"></div></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+}
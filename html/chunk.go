@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 )
 
@@ -30,47 +31,174 @@ func Render(chunk Chunk, ctx context.Context, w io.Writer) error {
 	}
 }
 
+// ChunkWriter collects the chunks of rendered nodes.
+//
+// The chunk writers of this package coalesce adjacent static chunks into a single chunk,
+// so a subtree without dynamic parts always results in one StaticChunk.
 type ChunkWriter interface {
 	Write(...Chunk)
+	// Chunks returns the chunks that were written. The returned slice is owned by the
+	// caller and the writer should not be written to afterwards.
 	Chunks() []Chunk
 }
 
-var chunkpool = sync.Pool{
-	New: func() any {
-		return &[]Chunk{}
-	},
+// staticwriter is implemented by the chunk writers of this package. Nodes append their
+// static output to the pending bytes of the writer directly, which avoids allocating
+// a chunk for every tag, attribute and text.
+type staticwriter interface {
+	static() *[]byte
+}
+
+// writestatic writes the concatenation of the given parts as static output.
+func writestatic(w ChunkWriter, parts ...string) {
+	if sw, ok := w.(staticwriter); ok {
+		buf := sw.static()
+		for _, part := range parts {
+			*buf = append(*buf, part...)
+		}
+		return
+	}
+	w.Write(StaticChunk(strings.Join(parts, "")))
+}
+
+// writeattr writes the rendered form of the attribute as static output, see appendattr.
+func writeattr(w ChunkWriter, attr *Attribute, mode RenderMode) {
+	if sw, ok := w.(staticwriter); ok {
+		buf := sw.static()
+		*buf = appendattr(*buf, attr.Key, attr.Value, attr.raw, mode)
+		return
+	}
+	w.Write(StaticChunk(appendattr(nil, attr.Key, attr.Value, attr.raw, mode)))
 }
 
 type cw struct {
-	buf *[]Chunk
+	chunks []Chunk
+	// Static output that is written as a single chunk before the next dynamic chunk
+	pending []byte
+	f       *format
+}
+
+func newcw(f *format) *cw {
+	return &cw{f: f}
+}
+
+func (cw *cw) format() *format {
+	return cw.f
+}
+
+func (cw *cw) static() *[]byte {
+	return &cw.pending
 }
 
 func (cw *cw) Write(chunks ...Chunk) {
-	*cw.buf = append(*cw.buf, chunks...)
+	for _, chunk := range chunks {
+		if static, ok := chunk.(StaticChunk); ok {
+			cw.pending = append(cw.pending, static...)
+			continue
+		}
+		cw.flush()
+		cw.chunks = append(cw.chunks, chunk)
+	}
 }
 
-func (cw cw) Chunks() []Chunk {
-	buf := *cw.buf
-	cw.Release()
-	return buf
+// flush moves the pending static output into a chunk, the chunk keeps the
+// bytes so the next static output starts with a new buffer.
+func (cw *cw) flush() {
+	if len(cw.pending) > 0 {
+		cw.chunks = append(cw.chunks, StaticChunk(cw.pending))
+		cw.pending = nil
+	}
 }
 
-func (cw cw) Release() {
-	chunkpool.Put(cw.buf)
+func (cw *cw) Chunks() []Chunk {
+	cw.flush()
+	chunks := cw.chunks
+	cw.chunks = nil
+	return chunks
 }
 
 func NewChunkWriter() ChunkWriter {
-	return &cw{buf: chunkpool.New().(*[]Chunk)}
+	return NewChunkWriterWithMode(ModeCompact)
+}
+
+// NewChunkWriterWithMode creates a ChunkWriter that formats the nodes written to it with the given mode.
+func NewChunkWriterWithMode(mode RenderMode) ChunkWriter {
+	return newcw(&format{mode: mode})
 }
 
+// teecw renders the chunks written to it to a writer right away, it is used to render
+// the nodes of components directly to the response at render time. Static output is
+// buffered until the next dynamic chunk or flush. Writers are pooled, see newteecw.
 type teecw struct {
-	ChunkWriter
-	fn func(Chunk) error
+	f       format
+	ctx     context.Context
+	out     io.Writer
+	pending []byte
+	// The first error of the underlying writer, nothing is written after it
+	err error
+}
+
+var teepool = sync.Pool{
+	New: func() any {
+		return &teecw{pending: make([]byte, 0, 4096)}
+	},
+}
+
+// newteecw takes a writer from the pool that starts with the given formatting state,
+// the writer must be released with release once it is flushed.
+func newteecw(f format, ctx context.Context, out io.Writer) *teecw {
+	cw := teepool.Get().(*teecw)
+	cw.f = f
+	cw.ctx = ctx
+	cw.out = out
+	return cw
+}
+
+func (cw *teecw) release() {
+	if cap(cw.pending) > 64*1024 {
+		// Do not hold on to the buffers of unusually large renders
+		cw.pending = make([]byte, 0, 4096)
+	}
+	cw.pending = cw.pending[:0]
+	cw.ctx = nil
+	cw.out = nil
+	cw.err = nil
+	teepool.Put(cw)
+}
+
+func (cw *teecw) format() *format {
+	return &cw.f
+}
+
+func (cw *teecw) static() *[]byte {
+	return &cw.pending
 }
 
 func (cw *teecw) Write(chunks ...Chunk) {
 	for _, chunk := range chunks {
-		cw.fn(chunk)
+		if cw.err != nil {
+			return
+		}
+		if static, ok := chunk.(StaticChunk); ok {
+			cw.pending = append(cw.pending, static...)
+			continue
+		}
+		if cw.flush() == nil {
+			cw.err = Render(chunk, cw.ctx, cw.out)
+		}
 	}
-	cw.ChunkWriter.Write(chunks...)
+}
+
+// flush writes the pending static output and returns the first error of the writer.
+func (cw *teecw) flush() error {
+	if cw.err == nil && len(cw.pending) > 0 {
+		_, cw.err = cw.out.Write(cw.pending)
+	}
+	cw.pending = cw.pending[:0]
+	return cw.err
+}
+
+// Chunks returns nil since the chunks are not kept.
+func (cw *teecw) Chunks() []Chunk {
+	return nil
 }
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/mathml"
+	"github.com/canpacis/pacis/html/svg"
+)
+
+type Options struct {
+	PackageName  string
+	FunctionName string
+	Out          string
+	Input        string
+}
+
+type TemplateData struct {
+	PackageName  string
+	FunctionName string
+	HTMLPackage  string
+	XPackage     string
+	UsesX        bool
+	UsesSVG      bool
+	UsesMathML   bool
+	Body         string
+}
+
+var filetempl = `package {{.PackageName}}
+
+import (
+	"{{.HTMLPackage}}"{{ if .UsesSVG }}
+	"{{.HTMLPackage}}/svg"{{ end }}{{ if .UsesMathML }}
+	"{{.HTMLPackage}}/mathml"{{ end }}{{ if .UsesX }}
+	"{{.XPackage}}"{{ end }}
+)
+
+func {{.FunctionName}}() html.Node {
+	return {{.Body}}
+}
+`
+
+// Tag names mapped to their constructors in the html package
+var elements = map[string]string{
+	"a": "A", "abbr": "Abbr", "address": "Address", "area": "Area", "article": "Article", "aside": "Aside", "audio": "Audio",
+	"b": "B", "base": "Base", "bdi": "Bdi", "bdo": "Bdo", "blockquote": "Blockquote", "body": "Body", "br": "Br", "button": "Button",
+	"canvas": "Canvas", "caption": "Caption", "cite": "Cite", "code": "Code", "col": "Col", "colgroup": "Colgroup",
+	"data": "DataEl", "datalist": "Datalist", "dd": "Dd", "del": "Del", "details": "Details", "dfn": "Dfn", "dialog": "Dialog",
+	"div": "Div", "dl": "Dl", "dt": "Dt", "em": "Em", "embed": "Embed", "fieldset": "Fieldset", "figcaption": "Figcaption",
+	"figure": "Figure", "footer": "Footer", "form": "Form", "h1": "H1", "h2": "H2", "h3": "H3", "h4": "H4", "h5": "H5", "h6": "H6",
+	"head": "Head", "header": "Header", "hgroup": "Hgroup", "hr": "Hr", "html": "Html", "i": "I", "iframe": "Iframe", "img": "Img",
+	"input": "Input", "ins": "Ins", "kbd": "Kbd", "label": "Label", "legend": "Legend", "li": "Li", "link": "Link", "main": "Main",
+	"map": "MapEl", "mark": "Mark", "menu": "Menu", "meta": "Meta", "meter": "Meter", "nav": "Nav", "noscript": "Noscript",
+	"object": "Object", "ol": "Ol", "optgroup": "Optgroup", "option": "Option", "output": "Output", "p": "P", "picture": "Picture",
+	"pre": "Pre", "progress": "Progress", "q": "Q", "rp": "Rp", "rt": "Rt", "ruby": "Ruby", "s": "S", "samp": "Samp",
+	"script": "Script", "search": "Search", "section": "Section", "select": "Select", "slot": "Slot", "small": "Small",
+	"source": "Source", "span": "Span", "strong": "Strong", "style": "Style", "sub": "Sub", "summary": "Summary", "sup": "Sup",
+	"table": "Table", "tbody": "Tbody", "td": "Td", "template": "Template", "textarea": "Textarea", "tfoot": "Tfoot", "th": "Th",
+	"thead": "Thead", "time": "Time", "title": "Title", "tr": "Tr", "track": "Track", "u": "U", "ul": "Ul", "var": "Var",
+	"video": "Video", "wbr": "Wbr",
+}
+
+// SVG tag names that have a constructor in the svg package, the constructor name is the
+// tag name with its first letter in upper case
+var svgelements = toset(
+	"a", "animate", "animateMotion", "animateTransform", "circle", "clipPath", "defs", "desc", "ellipse", "feBlend",
+	"feColorMatrix", "feComponentTransfer", "feComposite", "feConvolveMatrix", "feDiffuseLighting", "feDisplacementMap",
+	"feDistantLight", "feDropShadow", "feFlood", "feFuncA", "feFuncB", "feFuncG", "feFuncR", "feGaussianBlur", "feImage",
+	"feMerge", "feMergeNode", "feMorphology", "feOffset", "fePointLight", "feSpecularLighting", "feSpotLight", "feTile",
+	"feTurbulence", "filter", "foreignObject", "g", "image", "line", "linearGradient", "marker", "mask", "metadata", "mpath",
+	"path", "pattern", "polygon", "polyline", "radialGradient", "rect", "script", "set", "stop", "style", "svg", "switch",
+	"symbol", "text", "textPath", "title", "tspan", "use", "view",
+)
+
+// MathML tag names that have a constructor in the mathml package, named like the svg constructors
+var mathmlelements = toset(
+	"annotation", "math", "merror", "mfrac", "mi", "mmultiscripts", "mn", "mo", "mover", "mpadded", "mphantom",
+	"mprescripts", "mroot", "mrow", "ms", "mspace", "msqrt", "mstyle", "msub", "msubsup", "msup", "mtable", "mtd", "mtext",
+	"mtr", "munder", "munderover", "semantics",
+)
+
+func toset(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// Attribute keys mapped to their constructors in the html package
+var attributes = map[string]string{
+	"as": "As", "accept": "Accept", "accept-charset": "AcceptCharset", "accesskey": "AccessKey", "action": "Action",
+	"align": "Align", "alt": "Alt", "async": "Async", "autocomplete": "Autocomplete", "autofocus": "Autofocus",
+	"autoplay": "Autoplay", "charset": "Charset", "cite": "CiteAttr", "class": "Class", "color": "ColorAttr", "cols": "Cols",
+	"colspan": "Colspan", "content": "Content", "controls": "Controls", "coords": "Coords", "crossorigin": "Crossorigin",
+	"datetime": "Datetime", "default": "DefaultAttr", "dirname": "Dirname", "download": "Download", "draggable": "Draggable",
+	"enctype": "Enctype", "enterkeyhint": "EnterKeyHint", "for": "For", "form": "FormAttr", "formaction": "FormAction",
+	"headers": "Headers", "height": "Height", "hidden": "Hidden", "high": "High", "href": "Href", "hreflang": "HrefLang",
+	"http-equiv": "HttpEquiv", "id": "ID", "inert": "Inert", "inputmode": "InputMode", "ismap": "IsMap", "kind": "Kind",
+	"label": "LabelAttr", "src": "Src", "role": "Role", "lang": "Lang", "list": "List", "loop": "Loop", "low": "Low",
+	"max": "Max", "maxlength": "MaxLength", "media": "Media", "method": "Method", "min": "Min", "minlength": "MinLength",
+	"multiple": "Multiple", "muted": "Muted", "name": "Name", "placeholder": "Placeholder", "style": "StyleAttr",
+	"tabindex": "Tabindex", "title": "TitleAttr", "type": "Type", "rel": "Rel", "width": "Width", "value": "Value",
+	"shadowrootmode": "ShadowRootMode", "slot": "SlotAttr", "target": "Target", "property": "PropertyAttr",
+}
+
+// Alpine directives mapped to their constructors in the x package
+var directives = map[string]string{
+	"x-data": "Data", "x-init": "Init", "x-show": "Show", "x-text": "Text", "x-model": "Model", "x-modelable": "Modelable",
+	"x-for": "For", "x-effect": "Effect", "x-ref": "Ref", "x-teleport": "Teleport", "x-if": "If", "x-id": "ID",
+}
+
+// Alpine event modifiers mapped to their constants in the x package
+var modifiers = map[string]string{
+	"prevent": "Prevent", "stop": "Stop", "outside": "Outside", "window": "Window", "document": "Document", "once": "Once",
+	"debounce": "Debounce", "throttle": "Throttle", "self": "Self", "camel": "Camel", "dot": "Dot", "passive": "Passive",
+	"capture": "Capture",
+}
+
+// SVG attributes with string values mapped to their constructors in the svg package
+var svgattributes = map[string]string{
+	"d": "D", "fill": "Fill", "fill-rule": "FillRule", "clip-rule": "ClipRule", "stroke": "Stroke",
+	"stroke-linecap": "StrokeLinecap", "stroke-linejoin": "StrokeLinejoin", "offset": "Offset", "stop-color": "StopColor",
+	"gradientUnits": "GradientUnits", "patternUnits": "PatternUnits", "clip-path": "ClipPathAttr", "mask": "MaskAttr",
+	"filter": "FilterAttr", "text-anchor": "TextAnchor", "marker-end": "MarkerEnd", "marker-start": "MarkerStart",
+	"vector-effect": "VectorEffect", "preserveAspectRatio": "PreserveAspectRatio", "href": "Href",
+}
+
+// SVG attributes with number values mapped to their constructors in the svg package
+var svgnumbers = map[string]string{
+	"fill-opacity": "FillOpacity", "stroke-width": "StrokeWidth", "stroke-opacity": "StrokeOpacity",
+	"stroke-dashoffset": "StrokeDashoffset", "opacity": "Opacity", "width": "Width", "height": "Height", "x": "X", "y": "Y",
+	"x1": "X1", "y1": "Y1", "x2": "X2", "y2": "Y2", "cx": "Cx", "cy": "Cy", "r": "R", "rx": "Rx", "ry": "Ry", "dx": "Dx",
+	"dy": "Dy", "stop-opacity": "StopOpacity", "stdDeviation": "StdDeviation",
+}
+
+// MathML attributes with string values mapped to their constructors in the mathml package
+var mathmlattributes = map[string]string{
+	"display": "Display", "mathvariant": "MathVariant", "form": "Form", "linethickness": "LineThickness",
+	"encoding": "Encoding", "scriptlevel": "ScriptLevel", "lspace": "Lspace", "rspace": "Rspace", "width": "Width",
+	"height": "Height", "depth": "Depth",
+}
+
+// MathML attributes with boolean values mapped to their constructors in the mathml package
+var mathmlbooleans = map[string]string{
+	"displaystyle": "DisplayStyle", "stretchy": "Stretchy", "fence": "Fence", "separator": "Separator",
+	"accent": "Accent", "accentunder": "AccentUnder",
+}
+
+// numbers parses a list of numbers separated by single spaces, it fails if the numbers
+// would not be rendered back as they are written (e.g. "1.50" or "0,0").
+func numbers(value string) ([]string, bool) {
+	fields := strings.Split(value, " ")
+	for _, field := range fields {
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil || strconv.FormatFloat(n, 'f', -1, 64) != field {
+			return nil, false
+		}
+	}
+	return fields, true
+}
+
+type generator struct {
+	usesx      bool
+	usessvg    bool
+	usesmathml bool
+}
+
+// constructor returns the constructor call of the given element up to its first argument
+// and whether the call already takes the items as its only arguments.
+func (g *generator) constructor(el *html.Element) (string, bool) {
+	tag := el.Tag()
+
+	switch el.Namespace() {
+	case html.NamespaceSVG:
+		g.usessvg = true
+		if svgelements[tag] {
+			return fmt.Sprintf("svg.%s(", strings.ToUpper(tag[:1])+tag[1:]), true
+		}
+		return fmt.Sprintf("svg.El(%s", strconv.Quote(tag)), false
+	case html.NamespaceMathML:
+		g.usesmathml = true
+		if mathmlelements[tag] {
+			return fmt.Sprintf("mathml.%s(", strings.ToUpper(tag[:1])+tag[1:]), true
+		}
+		return fmt.Sprintf("mathml.El(%s", strconv.Quote(tag)), false
+	}
+
+	if constructor, ok := elements[tag]; ok {
+		return fmt.Sprintf("html.%s(", constructor), true
+	}
+	return fmt.Sprintf("html.El(%s", strconv.Quote(tag)), false
+}
+
+// foreign returns the constructor call of an attribute of an SVG or MathML element
+// if the svg or mathml package has a constructor for it.
+func (g *generator) foreign(ns html.Namespace, key, value string) (string, bool) {
+	quoted := strconv.Quote(value)
+
+	switch ns {
+	case html.NamespaceSVG:
+		if key == "xmlns" && value == svg.Namespace {
+			return "svg.XMLNS()", true
+		}
+		if constructor, ok := svgattributes[key]; ok {
+			return fmt.Sprintf("svg.%s(%s)", constructor, quoted), true
+		}
+		ns, ok := numbers(value)
+		switch {
+		case !ok:
+		case len(ns) == 1 && len(svgnumbers[key]) > 0:
+			return fmt.Sprintf("svg.%s(%s)", svgnumbers[key], ns[0]), true
+		case len(ns) == 4 && key == "viewBox":
+			return fmt.Sprintf("svg.ViewBox(%s)", strings.Join(ns, ", ")), true
+		case key == "stroke-dasharray":
+			return fmt.Sprintf("svg.StrokeDasharray(%s)", strings.Join(ns, ", ")), true
+		}
+	case html.NamespaceMathML:
+		if key == "xmlns" && value == mathml.Namespace {
+			return "mathml.XMLNS()", true
+		}
+		if constructor, ok := mathmlattributes[key]; ok {
+			return fmt.Sprintf("mathml.%s(%s)", constructor, quoted), true
+		}
+		if constructor, ok := mathmlbooleans[key]; ok && (value == "true" || value == "false") {
+			return fmt.Sprintf("mathml.%s(%s)", constructor, value), true
+		}
+	}
+	return "", false
+}
+
+func (g *generator) attribute(ns html.Namespace, key, value string) string {
+	if expr, ok := g.foreign(ns, key, value); ok {
+		return expr
+	}
+	quoted := strconv.Quote(value)
+
+	switch {
+	case key == "x-cloak" && len(value) == 0:
+		g.usesx = true
+		return "x.Cloak"
+	case key == "x-ignore" && len(value) == 0:
+		g.usesx = true
+		return "x.Ignore"
+	case len(directives[key]) > 0:
+		g.usesx = true
+		return fmt.Sprintf("x.%s(%s)", directives[key], quoted)
+	case strings.HasPrefix(key, "@"), strings.HasPrefix(key, "x-on:"):
+		g.usesx = true
+		parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(key, "@"), "x-on:"), ".")
+		args := []string{strconv.Quote(parts[0]), quoted}
+		for _, modifier := range parts[1:] {
+			if constant, ok := modifiers[modifier]; ok {
+				args = append(args, "x."+constant)
+			} else {
+				args = append(args, strconv.Quote(modifier))
+			}
+		}
+		return fmt.Sprintf("x.On(%s)", strings.Join(args, ", "))
+	case key == "x-bind":
+		g.usesx = true
+		return fmt.Sprintf("x.Bind(\"\", %s)", quoted)
+	case strings.HasPrefix(key, ":"), strings.HasPrefix(key, "x-bind:"):
+		g.usesx = true
+		attr := strings.TrimPrefix(strings.TrimPrefix(key, ":"), "x-bind:")
+		return fmt.Sprintf("x.Bind(%s, %s)", strconv.Quote(attr), quoted)
+	case strings.HasPrefix(key, "data-") && len(key) > 5:
+		return fmt.Sprintf("html.Data(%s, %s)", strconv.Quote(strings.TrimPrefix(key, "data-")), quoted)
+	case strings.HasPrefix(key, "aria-") && len(key) > 5:
+		return fmt.Sprintf("html.Aria(%s, %s)", strconv.Quote(strings.TrimPrefix(key, "aria-")), quoted)
+	case len(attributes[key]) > 0:
+		return fmt.Sprintf("html.%s(%s)", attributes[key], quoted)
+	default:
+		return fmt.Sprintf("html.Attr(%s, %s)", strconv.Quote(key), quoted)
+	}
+}
+
+// text collapses the insignificant whitespace of a text node, leading and trailing
+// whitespace is dropped for the first and last children of an element.
+func text(value string, first, last bool) string {
+	collapsed := strings.Join(strings.Fields(value), " ")
+	if len(collapsed) == 0 {
+		return ""
+	}
+	if !first && strings.ContainsAny(value[:1], " \t\r\n") {
+		collapsed = " " + collapsed
+	}
+	if !last && strings.ContainsAny(value[len(value)-1:], " \t\r\n") {
+		collapsed = collapsed + " "
+	}
+	return collapsed
+}
+
+func (g *generator) children(nodes []html.Node, preformatted bool) []string {
+	exprs := []string{}
+	for i, node := range nodes {
+		if expr := g.node(node, preformatted, i == 0, i == len(nodes)-1); len(expr) > 0 {
+			exprs = append(exprs, expr)
+		}
+	}
+	return exprs
+}
+
+func (g *generator) node(node html.Node, preformatted, first, last bool) string {
+	switch node := node.(type) {
+	case html.Text:
+		value := string(node)
+		if !preformatted {
+			value = text(value, first, last)
+		}
+		if len(value) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("html.Text(%s)", strconv.Quote(value))
+	case html.RawUnsafe:
+		if len(strings.TrimSpace(string(node))) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("html.RawUnsafe(%s)", strconv.Quote(string(node)))
+	case *html.Element:
+		tag := node.Tag()
+		if tag == "!DOCTYPE" {
+			return "html.Doctype"
+		}
+
+		props := []string{}
+		for key, value := range node.Attributes() {
+			props = append(props, g.attribute(node.Namespace(), key, value))
+		}
+		pre := preformatted || tag == "pre" || tag == "textarea"
+		children := g.children(node.GetNodes(), pre)
+
+		open, typed := g.constructor(node)
+		if !typed && (len(props) > 0 || len(children) > 0) {
+			open += ","
+		}
+		if len(props) == 0 && len(children) == 0 {
+			return open + ")"
+		}
+
+		buf := new(strings.Builder)
+		buf.WriteString(open + "\n")
+		for _, prop := range props {
+			buf.WriteString(prop + ",\n")
+		}
+		if len(props) > 0 && len(children) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, child := range children {
+			buf.WriteString(child + ",\n")
+		}
+		buf.WriteString(")")
+		return buf.String()
+	default:
+		return ""
+	}
+}
+
+func (g *generator) root(nodes html.Frag) string {
+	exprs := g.children(nodes, false)
+	switch len(exprs) {
+	case 0:
+		return "html.Fragment()"
+	case 1:
+		return exprs[0]
+	default:
+		return "html.Fragment(\n" + strings.Join(exprs, ",\n") + ",\n)"
+	}
+}
+
+func main() {
+	var options Options
+
+	flag.StringVar(&options.PackageName, "package", "components", "Generated go package name")
+	flag.StringVar(&options.FunctionName, "func", "Component", "Generated function name")
+	flag.StringVar(&options.Out, "out", "", "Output file, defaults to stdout")
+
+	flag.Parse()
+	options.Input = flag.Arg(0)
+
+	var input io.Reader = os.Stdin
+	if len(options.Input) > 0 && options.Input != "-" {
+		file, err := os.Open(options.Input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	source, err := convert(input, &options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(options.Out) == 0 {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := os.WriteFile(options.Out, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// convert parses the markup and generates the Go source of a function that returns it.
+func convert(input io.Reader, options *Options) ([]byte, error) {
+	nodes, err := html.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{}
+	data := &TemplateData{
+		PackageName:  options.PackageName,
+		FunctionName: options.FunctionName,
+		HTMLPackage:  "github.com/canpacis/pacis/html",
+		XPackage:     "github.com/canpacis/pacis/x",
+		Body:         g.root(nodes),
+	}
+	data.UsesX = g.usesx
+	data.UsesSVG = g.usessvg
+	data.UsesMathML = g.usesmathml
+	return generate(data)
+}
+
+func generate(data *TemplateData) ([]byte, error) {
+	tmp, err := template.New("html2pacis").Parse(filetempl)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmp.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
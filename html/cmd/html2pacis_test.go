@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of html2pacis")
+
+func TestConvert(t *testing.T) {
+	assert := assert.New(t)
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.html"))
+	assert.NoError(err)
+	assert.NotEmpty(inputs)
+
+	for _, input := range inputs {
+		file, err := os.Open(input)
+		assert.NoError(err)
+		source, err := convert(file, &Options{PackageName: "components", FunctionName: "Component"})
+		file.Close()
+		assert.NoError(err, input)
+
+		golden := strings.TrimSuffix(input, ".html") + ".golden"
+		if *update {
+			assert.NoError(os.WriteFile(golden, source, 0o644))
+			continue
+		}
+		expected, err := os.ReadFile(golden)
+		assert.NoError(err, "run the tests with -update to create the golden files")
+		assert.Equal(string(expected), string(source), input)
+	}
+}
@@ -0,0 +1,159 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+type reporterkey struct{}
+
+// WithErrorReporter returns a copy of ctx in which fn is called with the errors caught
+// by error boundaries while rendering with the returned context.
+func WithErrorReporter(ctx context.Context, fn func(error)) context.Context {
+	return context.WithValue(ctx, reporterkey{}, fn)
+}
+
+// reporterof returns the error reporter of the given context, falling back to the default logger.
+func reporterof(ctx context.Context) func(error) {
+	if ctx != nil {
+		if fn, ok := ctx.Value(reporterkey{}).(func(error)); ok {
+			return fn
+		}
+	}
+	return func(err error) {
+		slog.Default().Error("Error boundary caught an error", "error", err)
+	}
+}
+
+// SetErrorReporter sets the function that is called with the errors caught by error
+// boundaries while nodes are statically rendered to w, e.g. when a page is built. They are
+// reported to the default logger otherwise. Writers that are not implemented by this
+// package are left unchanged.
+func SetErrorReporter(w ChunkWriter, fn func(error)) {
+	if f, ok := w.(formatted); ok {
+		f.format().report = fn
+	}
+}
+
+// staticreporter returns the error reporter of the given chunk writer.
+func staticreporter(w ChunkWriter) func(error) {
+	if fn := formatof(w).report; fn != nil {
+		return fn
+	}
+	if tee, ok := w.(*teecw); ok {
+		// Components are rendered with the context of the response
+		return reporterof(tee.ctx)
+	}
+	return reporterof(nil)
+}
+
+// catch runs fn and turns a panic into an error.
+func catch(fn func() error) (err error) {
+	defer func() {
+		if data := recover(); data != nil {
+			if perr, ok := data.(error); ok {
+				err = fmt.Errorf("recovered from panic: %w", perr)
+			} else {
+				err = fmt.Errorf("recovered from panic: %v", data)
+			}
+		}
+	}()
+	return fn()
+}
+
+/*
+ErrorBoundaryNode catches the errors and panics that occur while rendering its subtree
+and renders a fallback node instead, so that a failing component does not abort or
+corrupt the whole response.
+
+Usage:
+
+	html.ErrorBoundary(
+		html.Component(PricingTable),
+		func(err error) html.Node {
+			return html.P(html.Text("Pricing is unavailable right now."))
+		},
+	)
+*/
+type ErrorBoundaryNode struct {
+	node     Node
+	fallback func(error) Node
+}
+
+// Implements the Item interface.
+func (*ErrorBoundaryNode) Item() {}
+
+// Implements the Node interface.
+func (b *ErrorBoundaryNode) Release() {
+	b.node.Release()
+}
+
+// Implements the Node interface.
+//
+// Errors that occur while the subtree is statically rendered are reported to the reporter
+// of the chunk writer, see SetErrorReporter. When the subtree contains dynamic chunks, its output is buffered on every render
+// and only written once all of the chunks rendered successfully, errors are then reported
+// to the reporter of the render context, see WithErrorReporter.
+func (b *ErrorBoundaryNode) Render(w ChunkWriter) error {
+	f := formatof(w)
+	snapshot := *f
+
+	inner := newcw(f)
+	if err := catch(func() error { return b.node.Render(inner) }); err != nil {
+		staticreporter(w)(err)
+		*f = snapshot
+		return b.fallback(err).Render(w)
+	}
+
+	chunks := inner.Chunks()
+	dynamic := false
+	for _, chunk := range chunks {
+		if _, ok := chunk.(DynamicChunk); ok {
+			dynamic = true
+			break
+		}
+	}
+	if !dynamic {
+		w.Write(chunks...)
+		return nil
+	}
+
+	w.Write(DynamicChunk(func(ctx context.Context, out io.Writer) error {
+		buf := new(bytes.Buffer)
+		err := catch(func() error {
+			for _, chunk := range chunks {
+				if err := Render(chunk, ctx, buf); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			_, err = buf.WriteTo(out)
+			return err
+		}
+
+		reporterof(ctx)(err)
+		state := snapshot
+		fallback := newcw(&state)
+		if err := b.fallback(err).Render(fallback); err != nil {
+			return err
+		}
+		for _, chunk := range fallback.Chunks() {
+			if err := Render(chunk, ctx, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return nil
+}
+
+// ErrorBoundary creates a node that renders the given node, or the node returned by fallback
+// if an error or a panic occurs while rendering it.
+func ErrorBoundary(node Node, fallback func(error) Node) *ErrorBoundaryNode {
+	return &ErrorBoundaryNode{node: node, fallback: fallback}
+}
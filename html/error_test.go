@@ -0,0 +1,93 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func renderContext(ctx context.Context, node html.Node) (string, error) {
+	cw := html.NewChunkWriter()
+	if err := node.Render(cw); err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, ctx, buf); err != nil {
+			return buf.String(), err
+		}
+	}
+	return buf.String(), nil
+}
+
+func TestComponentError(t *testing.T) {
+	assert := assert.New(t)
+
+	node := html.Div(html.Component(func(ctx context.Context) html.Node {
+		return html.Pre(html.JSON(make(chan int)))
+	}))
+	_, err := renderContext(context.Background(), node)
+	assert.Error(err)
+}
+
+func TestErrorBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	fallback := func(err error) html.Node {
+		return html.P(html.Text("Unavailable"))
+	}
+
+	reported := []error{}
+	ctx := html.WithErrorReporter(context.Background(), func(err error) {
+		reported = append(reported, err)
+	})
+
+	var node html.Node = html.Div(
+		html.ErrorBoundary(
+			html.Section(
+				html.Text("Pricing"),
+				html.Component(func(ctx context.Context) html.Node {
+					panic(errors.New("database is down"))
+				}),
+			),
+			fallback,
+		),
+		html.Span(html.Text("Footer")),
+	)
+	rendered, err := renderContext(ctx, node)
+	assert.NoError(err)
+	assert.Equal("<div><p>Unavailable</p><span>Footer</span></div>", rendered)
+	assert.Len(reported, 1)
+	assert.ErrorContains(reported[0], "database is down")
+
+	node = html.ErrorBoundary(
+		html.Component(func(ctx context.Context) html.Node {
+			return html.Pre(html.JSON(make(chan int)))
+		}),
+		fallback,
+	)
+	rendered, err = renderContext(ctx, node)
+	assert.NoError(err)
+	assert.Equal("<p>Unavailable</p>", rendered)
+	assert.Len(reported, 2)
+
+	node = html.ErrorBoundary(
+		html.Section(html.Component(func(ctx context.Context) html.Node {
+			return html.Text("Fine")
+		})),
+		fallback,
+	)
+	rendered, err = renderContext(ctx, node)
+	assert.NoError(err)
+	assert.Equal("<section>Fine</section>", rendered)
+	assert.Len(reported, 2)
+
+	node = html.ErrorBoundary(html.Pre(html.JSON(make(chan int))), fallback)
+	rendered, err = renderContext(ctx, node)
+	assert.NoError(err)
+	assert.Equal("<p>Unavailable</p>", rendered)
+}
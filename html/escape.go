@@ -0,0 +1,200 @@
+package html
+
+import (
+	"strings"
+)
+
+// attrContext describes how the value of an attribute is interpreted by the browser,
+// which in turn decides how the value is escaped when it is rendered.
+type attrContext int
+
+const (
+	// A plain attribute value, e.g. id, class or aria-label.
+	contextText = attrContext(iota)
+	// An attribute value that is interpreted as a URL, e.g. href or src.
+	contextURL
+	// An attribute value that is interpreted as a comma separated list of URLs, e.g. srcset.
+	contextSrcset
+	// An attribute value that is evaluated as JavaScript, e.g. onclick, x-show or @click.
+	contextJS
+	// An attribute value that is interpreted as CSS, e.g. style.
+	contextCSS
+)
+
+// unsafeValue replaces attribute values that are rejected by the escaper.
+// It is intentionally conspicuous, so that it is easy to spot in the rendered output.
+const unsafeValue = "ZpacisZ"
+
+// unsafeURL replaces URL attribute values with an unsafe scheme.
+const unsafeURL = "about:invalid#" + unsafeValue
+
+var urlattrs = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"codebase":   true,
+	"data":       true,
+	"formaction": true,
+	"href":       true,
+	"icon":       true,
+	"longdesc":   true,
+	"manifest":   true,
+	"ping":       true,
+	"poster":     true,
+	"src":        true,
+	"usemap":     true,
+	"xlink:href": true,
+}
+
+// contextOf returns the attribute context for the given attribute key.
+func contextOf(key string) attrContext {
+	key = strings.ToLower(key)
+
+	switch {
+	case urlattrs[key]:
+		return contextURL
+	case key == "srcset" || key == "imagesrcset":
+		return contextSrcset
+	case key == "style":
+		return contextCSS
+	case strings.HasPrefix(key, "@"), strings.HasPrefix(key, ":"), strings.HasPrefix(key, "x-"):
+		// Alpine directives, event listeners and bindings are all JavaScript expressions
+		return contextJS
+	case len(key) > 2 && strings.HasPrefix(key, "on"):
+		return contextJS
+	default:
+		return contextText
+	}
+}
+
+var textescaper = strings.NewReplacer(
+	`&`, "&amp;",
+	`"`, "&#34;",
+	`'`, "&#39;",
+	`<`, "&lt;",
+	`>`, "&gt;",
+)
+
+// Only the characters that can terminate a double quoted attribute value or start
+// a character reference are escaped in script and style contexts, so that the
+// expressions stay readable (e.g. `open = !open` or `() => close()`).
+var codeescaper = strings.NewReplacer(
+	`&`, "&amp;",
+	`"`, "&#34;",
+)
+
+var safeschemes = []string{"http", "https", "mailto", "tel", "sms"}
+
+// isSafeURL reports whether the given url is either relative or uses one of the allowed schemes.
+func isSafeURL(url string) bool {
+	url = strings.Map(func(r rune) rune {
+		// Browsers ignore these characters in the scheme, e.g. "java\tscript:"
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, url)
+
+	scheme, _, ok := strings.Cut(url, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		// No scheme, the url is relative
+		return true
+	}
+	scheme = strings.ToLower(scheme)
+	for _, safe := range safeschemes {
+		if scheme == safe {
+			return true
+		}
+	}
+	return false
+}
+
+var unsafecss = []string{"expression(", "javascript:", "vbscript:", "-moz-binding", "behavior:", "@import", "\\", "</"}
+
+// isSafeCSS reports whether the given css declaration list is free of constructs that
+// can execute script or load arbitrary resources.
+func isSafeCSS(css string) bool {
+	css = strings.ToLower(css)
+	// Strip comments that might be used to break up the keywords
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			css = css[:start]
+			break
+		}
+		css = css[:start] + css[start+2+end+2:]
+	}
+	css = strings.Join(strings.Fields(css), "")
+
+	for _, token := range unsafecss {
+		if strings.Contains(css, token) {
+			return false
+		}
+	}
+	return true
+}
+
+// EscapeAttr escapes the value of the attribute with the given key according to the context
+// the value is interpreted in by the browser. URL attributes with unsafe schemes (e.g. javascript:)
+// and style attributes with unsafe css are replaced with an inert value.
+func EscapeAttr(key, value string) string {
+	switch contextOf(key) {
+	case contextURL:
+		if !isSafeURL(value) {
+			return unsafeURL
+		}
+		return textescaper.Replace(value)
+	case contextSrcset:
+		candidates := strings.Split(value, ",")
+		for _, candidate := range candidates {
+			fields := strings.Fields(candidate)
+			if len(fields) > 0 && !isSafeURL(fields[0]) {
+				return unsafeURL
+			}
+		}
+		return textescaper.Replace(value)
+	case contextCSS:
+		if !isSafeCSS(value) {
+			return unsafeValue
+		}
+		return codeescaper.Replace(value)
+	case contextJS:
+		return codeescaper.Replace(value)
+	default:
+		return textescaper.Replace(value)
+	}
+}
+
+// appendattr appends the rendered form of an attribute to the given buffer. Attributes
+// with empty values are rendered as boolean attributes. Values are escaped according
+// to their context unless raw is true, and written without quotes in minify mode when possible.
+func appendattr(buf []byte, key, value string, raw bool, mode RenderMode) []byte {
+	buf = append(buf, ' ')
+	buf = append(buf, key...)
+	if mode == ModeXML {
+		// XML has no boolean attributes and no context dependent values
+		if !raw {
+			value = escapexml(value, true)
+		}
+		buf = append(buf, '=', '"')
+		buf = append(buf, value...)
+		return append(buf, '"')
+	}
+	if len(value) == 0 {
+		return buf
+	}
+	if !raw {
+		value = EscapeAttr(key, value)
+	}
+	if mode == ModeMinify && unquotable(value) {
+		buf = append(buf, '=')
+		return append(buf, value...)
+	}
+	buf = append(buf, '=', '"')
+	buf = append(buf, value...)
+	return append(buf, '"')
+}
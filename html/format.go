@@ -0,0 +1,191 @@
+package html
+
+import (
+	"strings"
+)
+
+// RenderMode controls the whitespace and markup form of the rendered output.
+type RenderMode int
+
+const (
+	// Renders the markup as it is constructed without any additional formatting.
+	ModeCompact = RenderMode(iota)
+	// Renders one element per line, indented by its depth in the tree.
+	// Useful for debugging and golden file tests.
+	ModePretty
+	// Collapses insignificant whitespace in text nodes, omits optional closing tags
+	// and drops quotes around attribute values where the HTML spec allows it.
+	ModeMinify
+	// Renders the tree as an XML document, see RenderXML. Tag and attribute names keep
+	// their case, elements without children self close and values are escaped by XML rules.
+	ModeXML
+)
+
+// format holds the formatting state of a chunk writer while a tree is being rendered.
+type format struct {
+	mode RenderMode
+	// Depth of the element that is currently being rendered
+	depth int
+	// Whether anything has been written yet, the first element is not preceded by a line break
+	started bool
+	// Greater than zero within elements whose whitespace is significant, e.g. <pre>
+	preserve int
+	// Whether the children of the current element are written on the same line
+	inline bool
+	// Set by a parent element to let the next element know that its closing tag can be omitted
+	omitclose bool
+	// Reports the errors that error boundaries catch while rendering statically, see SetErrorReporter
+	report func(error)
+	// Whether <script> and <style> elements get the nonce of the render context, see EnableNonce
+	nonce bool
+}
+
+type formatted interface {
+	format() *format
+}
+
+// formatof returns the formatting state of the given chunk writer. Writers that are not
+// implemented by this package always render in compact mode.
+func formatof(w ChunkWriter) *format {
+	if f, ok := w.(formatted); ok {
+		return f.format()
+	}
+	return &format{mode: ModeCompact}
+}
+
+// Line break followed by enough indentation for most documents, sliced by newline
+var indentation = "\n" + strings.Repeat("  ", 32)
+
+func (f *format) newline() string {
+	if 1+f.depth*2 <= len(indentation) {
+		return indentation[:1+f.depth*2]
+	}
+	return "\n" + strings.Repeat("  ", f.depth)
+}
+
+// whitespace sensitive elements keep their content as is in every mode.
+func preserves(tag string) bool {
+	switch tag {
+	case "pre", "textarea", "script", "style":
+		return true
+	default:
+		return false
+	}
+}
+
+// textonly reports whether the given nodes can be written on a single line.
+func textonly(nodes []Node) bool {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case Text, RawUnsafe:
+		case Frag:
+			if !textonly(node) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// flatten expands the fragments in the given nodes so that siblings can be inspected.
+func flatten(nodes []Node) []Node {
+	flat := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if frag, ok := node.(Frag); ok {
+			flat = append(flat, flatten(frag)...)
+		} else {
+			flat = append(flat, node)
+		}
+	}
+	return flat
+}
+
+func tagof(node Node) string {
+	if el, ok := node.(*Element); ok {
+		return el.Tag()
+	}
+	return ""
+}
+
+func oneof(tag string, tags ...string) bool {
+	for _, candidate := range tags {
+		if tag == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// Elements that close a preceding <p> element when they start
+var pclosers = []string{
+	"address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset", "figcaption", "figure", "footer",
+	"form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav", "ol", "p", "pre", "search",
+	"section", "table", "ul",
+}
+
+// omittable reports whether the closing tag of the element can be omitted according to
+// https://html.spec.whatwg.org/multipage/syntax.html#optional-tags. next is the following
+// sibling of the element or nil if it is the last child of its parent.
+func omittable(el *Element, next Node, parent string) bool {
+	last := next == nil
+	nexttag := tagof(next)
+
+	switch el.Tag() {
+	case "li":
+		return last || nexttag == "li"
+	case "dt":
+		return oneof(nexttag, "dt", "dd")
+	case "dd":
+		return last || oneof(nexttag, "dt", "dd")
+	case "p":
+		if last {
+			return !oneof(parent, "a", "audio", "del", "ins", "map", "noscript", "video") && !strings.Contains(parent, "-")
+		}
+		return oneof(nexttag, pclosers...)
+	case "option":
+		return last || oneof(nexttag, "option", "optgroup", "hr")
+	case "optgroup":
+		return last || oneof(nexttag, "optgroup", "hr")
+	case "rt", "rp":
+		return last || oneof(nexttag, "rt", "rp")
+	case "thead":
+		return oneof(nexttag, "tbody", "tfoot")
+	case "tbody":
+		return last || oneof(nexttag, "tbody", "tfoot")
+	case "tfoot":
+		return last
+	case "tr":
+		return last || nexttag == "tr"
+	case "td", "th":
+		return last || oneof(nexttag, "td", "th")
+	default:
+		return false
+	}
+}
+
+// collapse replaces runs of whitespace with a single space.
+func collapse(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\r', '\f':
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+		default:
+			b.WriteRune(r)
+			space = false
+		}
+	}
+	return b.String()
+}
+
+// unquotable reports whether an escaped attribute value can be written without quotes.
+func unquotable(value string) bool {
+	return len(value) > 0 && !strings.ContainsAny(value, " \t\n\r\f\"'=<>`")
+}
@@ -0,0 +1,100 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func renderMode(node html.Node, mode html.RenderMode) string {
+	cw := html.NewChunkWriterWithMode(mode)
+	node.Render(cw)
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		html.Render(chunk, context.Background(), buf)
+	}
+	return buf.String()
+}
+
+func page() html.Node {
+	return html.Fragment(
+		html.Doctype,
+		html.Html(
+			html.Body(
+				html.Main(
+					html.ID("app"),
+					html.Class("flex gap-2"),
+
+					html.P(html.Text("  Hello,\n   World!  ")),
+					html.Ul(
+						html.Li(html.Text("One")),
+						html.Li(html.Text("Two")),
+					),
+					html.Pre(html.Text("a\n  b")),
+					html.Img(html.Src("/logo.png"), html.Alt("")),
+				),
+			),
+		),
+	)
+}
+
+func TestPrettyMode(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := `<!DOCTYPE html>
+<html>
+  <body>
+    <main id="app" class="flex gap-2">
+      <p>  Hello,
+   World!  </p>
+      <ul>
+        <li>One</li>
+        <li>Two</li>
+      </ul>
+      <pre>a
+  b</pre>
+      <img src="/logo.png" alt>
+    </main>
+  </body>
+</html>`
+	assert.Equal(expected, renderMode(page(), html.ModePretty))
+
+	mixed := html.Div(html.Text(" Hello "), html.B(html.Text("World")))
+	assert.Equal("<div>\n  Hello\n  <b>World</b>\n</div>", renderMode(mixed, html.ModePretty))
+}
+
+func TestMinifyMode(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := `<!DOCTYPE html><html><body><main id=app class="flex gap-2"><p> Hello, World! <ul><li>One<li>Two</ul><pre>a
+  b</pre><img src=/logo.png alt></main></body></html>`
+	assert.Equal(expected, renderMode(page(), html.ModeMinify))
+
+	tests := []struct {
+		Node     html.Node
+		Rendered string
+	}{
+		{html.A(html.P(html.Text("text"))), "<a><p>text</p></a>"},
+		{html.Div(html.P(html.Text("a")), html.Span()), "<div><p>a</p><span></span></div>"},
+		{html.Dl(html.Dt(html.Text("a")), html.Dd(html.Text("b"))), "<dl><dt>a<dd>b</dl>"},
+		{html.Table(html.Tbody(html.Tr(html.Td(), html.Td()))), "<table><tbody><tr><td><td></table>"},
+		{html.Ul(html.Li(), html.Fragment(html.Li())), "<ul><li><li></ul>"},
+		{html.Div(html.Data("value", "a b"), html.Data("empty", "")), `<div data-value="a b" data-empty></div>`},
+	}
+	for _, test := range tests {
+		assert.Equal(test.Rendered, renderMode(test.Node, html.ModeMinify))
+	}
+}
+
+func TestModeComponent(t *testing.T) {
+	assert := assert.New(t)
+
+	node := html.Div(html.Component(func(ctx context.Context) html.Node {
+		return html.Ul(html.Li(html.Text("One")))
+	}))
+	assert.Equal("<div>\n  <ul>\n    <li>One</li>\n  </ul>\n</div>", renderMode(node, html.ModePretty))
+	assert.Equal("<div><ul><li>One</ul></div>", renderMode(node, html.ModeMinify))
+}
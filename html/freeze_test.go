@@ -0,0 +1,112 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/canpacis/pacis/components"
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/x"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := html.A(html.Href("/"), html.Span(html.Text("Home"))).Freeze()
+	assert.True(shared.Frozen())
+	assert.True(shared.GetNodes()[0].(*html.Element).Frozen())
+	assert.True(html.Doctype.Frozen())
+
+	assert.Panics(func() { shared.SetAttribute("id", "home") })
+	assert.Panics(func() { shared.AddClass("link") })
+	assert.Panics(func() { shared.AppendNode(html.Text("!")) })
+
+	// Copy on write
+	mutable := shared.Mutable()
+	assert.False(mutable.Frozen())
+	mutable.SetAttribute("id", "home")
+	assert.Equal(`<a href="/" id="home"><span>Home</span></a>`, render(mutable))
+	assert.Equal(`<a href="/"><span>Home</span></a>`, render(shared))
+
+	// Frozen nodes are skipped when their parents are released
+	html.Div(shared, html.Doctype).Release()
+	html.Div(html.ID("reused"))
+	assert.Equal(`<a href="/"><span>Home</span></a>`, render(shared))
+	assert.Equal(`<!DOCTYPE html>`, render(html.Doctype))
+
+	// Properties that modify their children do not modify frozen children
+	button := html.Button(html.Class("btn"), components.AsChild, shared)
+	assert.Equal(`<a href="/" class="btn"><span>Home</span></a>`, render(button))
+	assert.Equal(`<a href="/"><span>Home</span></a>`, render(shared))
+}
+
+// Run with the race detector, e.g. go test -race ./html, to check that shared nodes are safe to
+// use from many goroutines at once.
+func TestConcurrentRender(t *testing.T) {
+	assert := assert.New(t)
+
+	nav := html.Nav(
+		html.A(html.Href("/"), html.Text("Home")),
+		html.A(html.Href("/docs"), html.Text("Docs")),
+	).Freeze()
+	link := html.A(html.Href("/login"), html.Text("Sign in")).Freeze()
+
+	page := func(ctx context.Context) html.Node {
+		return html.Fragment(
+			html.Doctype,
+			html.Body(
+				x.Cloak,
+				nav,
+				html.Div(x.Ignore, html.Text(ctx.Value(namekey{}).(string))),
+				html.Button(html.Class("btn"), components.AsChild, link),
+			),
+		)
+	}
+	expected := `<!DOCTYPE html><body x-cloak><nav><a href="/">Home</a><a href="/docs">Docs</a></nav>` +
+		`<div x-ignore>Jane</div><a href="/login" class="btn">Sign in</a></body>`
+
+	component := html.Component(page)
+	cw := html.NewChunkWriter()
+	assert.NoError(component.Render(cw))
+	chunks := cw.Chunks()
+
+	var wg sync.WaitGroup
+	results := make(chan string, 64)
+	for range 32 {
+		wg.Add(2)
+		ctx := context.WithValue(context.Background(), namekey{}, "Jane")
+
+		// Trees that are built, rendered and released per goroutine
+		go func() {
+			defer wg.Done()
+			node := page(ctx)
+			buf := new(bytes.Buffer)
+			cw := html.NewChunkWriter()
+			node.Render(cw)
+			for _, chunk := range cw.Chunks() {
+				html.Render(chunk, ctx, buf)
+			}
+			node.Release()
+			results <- buf.String()
+		}()
+
+		// Chunks that are built once and rendered per goroutine
+		go func() {
+			defer wg.Done()
+			buf := new(bytes.Buffer)
+			for _, chunk := range chunks {
+				html.Render(chunk, ctx, buf)
+			}
+			results <- buf.String()
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	for result := range results {
+		assert.Equal(expected, result)
+	}
+}
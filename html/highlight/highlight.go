@@ -0,0 +1,246 @@
+// Package highlight highlights source code on the server and renders it as html.Span nodes
+// with theme classes, so that code samples need no client side highlighter. Go, HTML, CSS,
+// JavaScript, TypeScript, JSON, shell and YAML are supported.
+//
+// Example usage:
+//
+//	highlight.Code(source, &highlight.Options{
+//		Language:    "go",
+//		LineNumbers: true,
+//		Highlight:   highlight.Lines("3-5"),
+//	})
+//
+// Code blocks of Markdown documents are highlighted with CodeBlock:
+//
+//	markdown.New(&markdown.Options{CodeBlock: highlight.CodeBlock})
+//
+// The tokens get classes like "hl-keyword" and "hl-string", Theme is a stylesheet for them
+// with light and dark colors.
+package highlight
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/markdown"
+)
+
+var languages = map[string]func(string) []Token{
+	"go":         golang.lex,
+	"golang":     golang.lex,
+	"html":       lexhtml,
+	"xml":        lexhtml,
+	"svg":        lexhtml,
+	"css":        css.lex,
+	"js":         javascript.lex,
+	"javascript": javascript.lex,
+	"jsx":        javascript.lex,
+	"mjs":        javascript.lex,
+	"ts":         javascript.lex,
+	"typescript": javascript.lex,
+	"tsx":        javascript.lex,
+	"json":       json.lex,
+	"sh":         shell.lex,
+	"bash":       shell.lex,
+	"shell":      shell.lex,
+	"zsh":        shell.lex,
+	"yaml":       yaml.lex,
+	"yml":        yaml.lex,
+}
+
+// Supported reports whether the language, or one of its aliases (e.g. "ts" or "yml"), is supported.
+func Supported(language string) bool {
+	_, ok := languages[strings.ToLower(language)]
+	return ok
+}
+
+// Tokenize splits the code into tokens of the given language. The code of an unsupported
+// language is a single text token.
+func Tokenize(language, code string) []Token {
+	lex, ok := languages[strings.ToLower(language)]
+	if !ok {
+		if len(code) == 0 {
+			return []Token{}
+		}
+		return []Token{{Kind: Text, Text: code}}
+	}
+	return lex(code)
+}
+
+type Options struct {
+	Language string
+	// Whether the lines are numbered
+	LineNumbers bool
+	// Number of the first line, defaults to 1
+	StartLine int
+	// Ranges of the highlighted lines counted from 1 regardless of StartLine, see Lines
+	Highlight []Range
+	// Whether the lines starting with + and - are marked as added and removed lines.
+	// The markers are rendered in their own spans and are not part of the highlighted code.
+	Diff bool
+}
+
+// Range is an inclusive range of line numbers, a single line has the same From and To.
+type Range struct {
+	From, To int
+}
+
+// Lines parses a comma separated list of line numbers and ranges, e.g. "1,3-5".
+func Lines(spec string) []Range {
+	lines := []Range{}
+	for part := range strings.SplitSeq(spec, ",") {
+		from, to, isrange := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isrange {
+			if end, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		lines = append(lines, Range{From: start, To: end})
+	}
+	return lines
+}
+
+/*
+Code highlights the code and renders it as a <pre> element. Each line is a span with
+the "hl-line" class that contains the spans of its tokens:
+
+	<pre class="hl" data-language="go"><code>
+		<span class="hl-line" data-line="1"><span class="hl-keyword">package</span> main</span>
+		<span class="hl-line hl-highlighted" data-line="2">...</span>
+	</code></pre>
+
+Line numbers are rendered as "hl-number-line" spans at the start of the lines and diff
+markers as "hl-marker" spans, the lines get the "hl-added" and "hl-removed" classes.
+*/
+func Code(code string, options *Options) html.Node {
+	if options == nil {
+		options = &Options{}
+	}
+	start := options.StartLine
+	if start == 0 {
+		start = 1
+	}
+
+	source := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	markers := make([]byte, len(source))
+	if options.Diff {
+		for i, line := range source {
+			if len(line) > 0 && strings.IndexByte("+- ", line[0]) >= 0 {
+				markers[i] = line[0]
+				source[i] = line[1:]
+			}
+		}
+	}
+
+	lines := [][]html.Item{{}}
+	for _, token := range Tokenize(options.Language, strings.Join(source, "\n")) {
+		for i, part := range strings.Split(token.Text, "\n") {
+			if i > 0 {
+				lines = append(lines, []html.Item{})
+			}
+			if len(part) == 0 {
+				continue
+			}
+			last := len(lines) - 1
+			if class := token.Kind.Class(); len(class) > 0 {
+				lines[last] = append(lines[last], html.Span(html.Class(class), html.Text(part)))
+			} else {
+				lines[last] = append(lines[last], html.Text(part))
+			}
+		}
+	}
+
+	// Ranges are clamped to the lines of the code
+	highlighted := make([]bool, len(lines))
+	for _, r := range options.Highlight {
+		for line := max(r.From, 1); line <= min(r.To, len(lines)); line++ {
+			highlighted[line-1] = true
+		}
+	}
+
+	items := []html.Item{}
+	for i, content := range lines {
+		number := start + i
+		class := "hl-line"
+		if highlighted[i] {
+			class += " hl-highlighted"
+		}
+		switch markers[i] {
+		case '+':
+			class += " hl-added"
+		case '-':
+			class += " hl-removed"
+		}
+
+		line := []html.Item{html.Class(class), html.Data("line", strconv.Itoa(number))}
+		if options.LineNumbers {
+			line = append(line, html.Span(html.Class("hl-number-line"), html.Aria("hidden", "true"), html.Text(strconv.Itoa(number))))
+		}
+		if options.Diff {
+			marker := " "
+			if markers[i] != 0 {
+				marker = string(markers[i])
+			}
+			line = append(line, html.Span(html.Class("hl-marker"), html.Aria("hidden", "true"), html.Text(marker)))
+		}
+		if i > 0 {
+			items = append(items, html.Text("\n"))
+		}
+		items = append(items, html.Span(append(line, content...)...))
+	}
+
+	pre := []html.Item{html.Class("hl")}
+	if len(options.Language) > 0 {
+		pre = append(pre, html.Data("language", strings.ToLower(options.Language)))
+	}
+	return html.Pre(append(pre, html.Code(items...))...)
+}
+
+/*
+CodeBlock highlights a code block of a Markdown document, it is meant to be used as the
+CodeBlock option of the markdown package. The words of the info string after the language
+configure the block:
+
+  - {1,3-5} highlights the given lines
+  - showLineNumbers numbers the lines, showLineNumbers{10} starts at the given number
+  - diff marks the lines starting with + and - as added and removed lines, like the "diff" language
+
+Usage:
+
+	```go {3} showLineNumbers
+	package main
+
+	func main() {}
+	```
+*/
+func CodeBlock(block markdown.CodeBlock) html.Node {
+	options := &Options{Language: block.Language}
+	if options.Language == "diff" {
+		options.Diff = true
+	}
+	words := strings.Fields(block.Info)
+	if len(words) > 0 {
+		// The first word is the language
+		words = words[1:]
+	}
+	for _, word := range words {
+		switch {
+		case strings.HasPrefix(word, "{") && strings.HasSuffix(word, "}"):
+			options.Highlight = append(options.Highlight, Lines(word[1:len(word)-1])...)
+		case word == "showLineNumbers":
+			options.LineNumbers = true
+		case strings.HasPrefix(word, "showLineNumbers{") && strings.HasSuffix(word, "}"):
+			options.LineNumbers = true
+			options.StartLine, _ = strconv.Atoi(word[len("showLineNumbers{") : len(word)-1])
+		case word == "diff":
+			options.Diff = true
+		}
+	}
+	return Code(block.Code, options)
+}
@@ -0,0 +1,89 @@
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/canpacis/pacis/html/highlight"
+	"github.com/canpacis/pacis/html/htmltest"
+	"github.com/canpacis/pacis/html/markdown"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]highlight.Token{
+		{Kind: highlight.Keyword, Text: "func"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Function, Text: "main"},
+		{Kind: highlight.Punctuation, Text: "("},
+		{Kind: highlight.Punctuation, Text: ")"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Punctuation, Text: "{"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Builtin, Text: "println"},
+		{Kind: highlight.Punctuation, Text: "("},
+		{Kind: highlight.String, Text: `"hi"`},
+		{Kind: highlight.Punctuation, Text: ")"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Punctuation, Text: "}"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Comment, Text: "// done"},
+	}, highlight.Tokenize("go", `func main() { println("hi") } // done`))
+
+	assert.Equal([]highlight.Token{
+		{Kind: highlight.Punctuation, Text: "{"},
+		{Kind: highlight.Property, Text: `"a"`},
+		{Kind: highlight.Punctuation, Text: ":"},
+		{Kind: highlight.Text, Text: " "},
+		{Kind: highlight.Number, Text: "1"},
+		{Kind: highlight.Punctuation, Text: "}"},
+	}, highlight.Tokenize("json", `{"a": 1}`))
+
+	tokens := highlight.Tokenize("html", `<script>let a = 1 < 2</script>`)
+	assert.Contains(tokens, highlight.Token{Kind: highlight.Tag, Text: "script"})
+	assert.Contains(tokens, highlight.Token{Kind: highlight.Keyword, Text: "let"})
+	assert.Contains(tokens, highlight.Token{Kind: highlight.Operator, Text: "<"})
+
+	assert.Equal([]highlight.Token{{Kind: highlight.Text, Text: "plain"}}, highlight.Tokenize("cobol", "plain"))
+	assert.True(highlight.Supported("TS"))
+	assert.Equal([]highlight.Range{{From: 1, To: 1}, {From: 3, To: 5}}, highlight.Lines("1, 3-5, x"))
+}
+
+func TestCode(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := htmltest.Render(t, highlight.Code("+x := 1\n-y := 2\n z := 3\n", &highlight.Options{
+		Language:    "go",
+		LineNumbers: true,
+		StartLine:   10,
+		Highlight:   []highlight.Range{{From: 3, To: 3}},
+		Diff:        true,
+	}))
+	assert.True(doc.AttrEquals("pre", "data-language", "go"))
+	assert.True(doc.HasElement("pre.hl > code > span.hl-line.hl-added > span.hl-number-line"))
+	assert.True(doc.AttrEquals("span.hl-removed", "data-line", "11"))
+	assert.True(doc.AttrEquals("span.hl-highlighted", "data-line", "12"))
+	assert.True(doc.TextContains("span.hl-added > span.hl-marker", "+"))
+	assert.True(doc.TextContains("span.hl-added > span.hl-number", "1"))
+
+	assert.Equal(
+		`<pre class="hl"><code><span class="hl-line" data-line="1">a &lt; b</span></code></pre>`,
+		htmltest.Render(t, highlight.Code("a < b", nil)).String(),
+	)
+
+	// Huge ranges are clamped to the lines of the code
+	doc = htmltest.Render(t, highlight.Code("a\nb", &highlight.Options{Highlight: highlight.Lines("0-2000000000")}))
+	assert.Len(doc.QueryAll("span.hl-highlighted"), 2)
+}
+
+func TestCodeBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	md := markdown.New(&markdown.Options{CodeBlock: highlight.CodeBlock})
+	doc := htmltest.Render(t, md.Parse("```js {2} showLineNumbers{5}\nconst a = 1\nconst b = 2\n```"))
+	assert.True(doc.AttrEquals("pre.hl", "data-language", "js"))
+	assert.True(doc.TextContains("span.hl-keyword", "const"))
+	assert.True(doc.AttrEquals("span.hl-highlighted", "data-line", "6"))
+	assert.True(doc.TextContains("span.hl-number-line", "5"))
+}
@@ -0,0 +1,377 @@
+package highlight
+
+import (
+	"strings"
+)
+
+var (
+	whitespace   = rule{pattern: re(`\s+`), kind: Text}
+	linecomment  = rule{pattern: re(`//[^\n]*`), kind: Comment}
+	blockcomment = rule{pattern: re(`(?s)/\*.*?(?:\*/|$)`), kind: Comment}
+	doublequoted = rule{pattern: re(`"(?:\\.|[^"\\\n])*"?`), kind: String}
+	singlequoted = rule{pattern: re(`'(?:\\.|[^'\\\n])*'?`), kind: String}
+)
+
+// Go
+
+var (
+	gokeywords  = words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var")
+	gotypes     = words("any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr")
+	gobuiltins  = words("append cap clear close complex copy delete imag len make max min new panic print println real recover")
+	goconstants = words("true false iota nil")
+)
+
+var golang = rules{
+	whitespace,
+	linecomment,
+	blockcomment,
+	{pattern: re("`[^`]*`?"), kind: String},
+	doublequoted,
+	{pattern: re(`'(?:\\.|[^'\\\n])+'`), kind: String},
+	{pattern: re(`(?:0[xX][0-9a-fA-F_]+|0[bB][01_]+|0[oO][0-7_]+|(?:\d[\d_]*(?:\.[\d_]*)?|\.\d[\d_]*)(?:[eEpP][+-]?\d+)?)i?`), kind: Number},
+	{pattern: re(`[\p{L}_][\p{L}\p{N}_]*`), classify: func(match, rest string, tokens []Token) Kind {
+		switch {
+		case gokeywords[match]:
+			return Keyword
+		case goconstants[match]:
+			return Constant
+		case gotypes[match]:
+			return Type
+		case gobuiltins[match] && call(rest):
+			return Builtin
+		case call(rest):
+			return Function
+		}
+		if prev, ok := significant(tokens); ok && prev.Kind == Keyword && prev.Text == "type" {
+			return Type
+		}
+		return Text
+	}},
+	{pattern: re(`<-|:=|\.\.\.|&&|\|\||\+\+|--|<<=?|>>=?|&\^=?|[-+*/%&|^=!<>]=?|~`), kind: Operator},
+	{pattern: re(`[{}()\[\];,.:]`), kind: Punctuation},
+}
+
+// JavaScript and TypeScript
+
+var (
+	jskeywords = words(`as async await break case catch class const continue debugger default delete do else enum export extends
+		finally for from function get if implements import in instanceof interface let new of private protected public readonly
+		return satisfies set static super switch this throw try type typeof var void while with yield
+		abstract declare infer is keyof module namespace`)
+	jstypes     = words("any bigint boolean never number object string symbol unknown")
+	jsbuiltins  = words("Array Boolean Date Error JSON Map Math Number Object Promise Proxy Reflect RegExp Set String Symbol WeakMap WeakSet console document globalThis window")
+	jsconstants = words("true false null undefined NaN Infinity")
+)
+
+// operand reports whether an operand is expected at the position, a slash starts a
+// regular expression there instead of a division.
+func operand(rest string, tokens []Token) bool {
+	prev, ok := significant(tokens)
+	if !ok {
+		return true
+	}
+	switch prev.Kind {
+	case Operator, Keyword:
+		return true
+	case Punctuation:
+		return !strings.ContainsAny(prev.Text, ")]}")
+	}
+	return false
+}
+
+var javascript = rules{
+	whitespace,
+	linecomment,
+	blockcomment,
+	{pattern: re("`(?:\\\\[\\s\\S]|[^`\\\\])*`?"), kind: String},
+	doublequoted,
+	singlequoted,
+	{pattern: re(`/(?:\\.|\[(?:\\.|[^\]\\\n])*\]|[^/\\\n\[])+/[dgimsuvy]*`), kind: String, when: operand},
+	{pattern: re(`(?:0[xX][\da-fA-F_]+|0[bB][01_]+|0[oO][0-7_]+|(?:\d[\d_]*(?:\.[\d_]*)?|\.\d[\d_]*)(?:[eE][+-]?\d+)?)n?`), kind: Number},
+	{pattern: re(`@[\p{L}_$][\p{L}\p{N}_$]*`), kind: Attribute},
+	{pattern: re(`#?[\p{L}_$][\p{L}\p{N}_$]*`), classify: func(match, rest string, tokens []Token) Kind {
+		prev, _ := significant(tokens)
+		member := prev.Kind == Punctuation && (prev.Text == "." || prev.Text == "?.")
+		switch {
+		case member && call(rest):
+			return Function
+		case member:
+			return Property
+		case jskeywords[match]:
+			return Keyword
+		case jsconstants[match]:
+			return Constant
+		case jstypes[match]:
+			return Type
+		case jsbuiltins[match]:
+			return Builtin
+		case call(rest):
+			return Function
+		}
+		return Text
+	}},
+	{pattern: re(`\?\.`), kind: Punctuation},
+	{pattern: re(`=>|\.\.\.|\?\?=?|===?|!==?|\*\*=?|&&=?|\|\|=?|\+\+|--|<<=?|>>>?=?|[-+*/%&|^<>!=~?]=?`), kind: Operator},
+	{pattern: re(`[{}()\[\];,.:]`), kind: Punctuation},
+}
+
+// CSS
+
+// declaration reports whether the position is within a declaration rather than a selector.
+func declaration(rest string) bool {
+	i := strings.IndexAny(rest, "{};")
+	return i < 0 || rest[i] != '{'
+}
+
+var css = rules{
+	whitespace,
+	blockcomment,
+	doublequoted,
+	singlequoted,
+	{pattern: re(`@[\w-]+`), kind: Keyword},
+	{pattern: re(`!important`), kind: Keyword},
+	{pattern: re(`#[\w-]+`), classify: func(match, rest string, tokens []Token) Kind {
+		if declaration(rest) {
+			return Number
+		}
+		return Attribute
+	}},
+	{pattern: re(`-?(?:\d+(?:\.\d+)?|\.\d+)(?:%|[a-zA-Z]+)?`), kind: Number},
+	{pattern: re(`\.-?[a-zA-Z_][\w-]*`), kind: Attribute},
+	{pattern: re(`::?[a-zA-Z][\w-]*`), kind: Attribute, when: func(rest string, tokens []Token) bool {
+		return !declaration(rest)
+	}},
+	{pattern: re(`-{0,2}[a-zA-Z_][\w-]*`), classify: func(match, rest string, tokens []Token) Kind {
+		switch {
+		case call(rest):
+			return Function
+		case !declaration(rest):
+			return Tag
+		case strings.HasPrefix(strings.TrimLeft(rest, " \t"), ":"):
+			if strings.HasPrefix(match, "--") {
+				return Variable
+			}
+			return Property
+		case strings.HasPrefix(match, "--"):
+			return Variable
+		}
+		return Constant
+	}},
+	{pattern: re(`[{}()\[\];,:>+~*=]`), kind: Punctuation},
+}
+
+// JSON
+
+var json = rules{
+	whitespace,
+	{pattern: re(`"(?:\\.|[^"\\\n])*"`), classify: func(match, rest string, tokens []Token) Kind {
+		if strings.HasPrefix(strings.TrimLeft(rest, " \t\r\n"), ":") {
+			return Property
+		}
+		return String
+	}},
+	{pattern: re(`-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?`), kind: Number},
+	{pattern: re(`(?:true|false|null)\b`), kind: Constant},
+	{pattern: re(`[{}\[\],:]`), kind: Punctuation},
+}
+
+// Shell
+
+var (
+	shkeywords = words("if then else elif fi for while until do done case esac in function select return export local readonly declare unset source alias")
+	shbuiltins = words("echo cd pwd exit set test read printf eval exec trap shift type command")
+)
+
+// command reports whether the position is where a shell command starts.
+func command(tokens []Token) bool {
+	if linestart(tokens) {
+		return true
+	}
+	prev, ok := significant(tokens)
+	if !ok {
+		return true
+	}
+	return prev.Kind == Operator || (prev.Kind == Keyword && prev.Text != "in") ||
+		(prev.Kind == Punctuation && strings.HasSuffix(prev.Text, "("))
+}
+
+var shell = rules{
+	{pattern: re(`[ \t]+|\\\n|\n`), kind: Text},
+	{pattern: re(`#[^\n]*`), kind: Comment, when: func(rest string, tokens []Token) bool {
+		return wordstart(tokens)
+	}},
+	{pattern: re(`'[^']*'?`), kind: String},
+	{pattern: re(`\$'(?:\\.|[^'\\])*'?`), kind: String},
+	{pattern: re(`"(?:\\.|[^"\\])*"?`), kind: String},
+	{pattern: re(`\$\(`), kind: Punctuation},
+	{pattern: re(`\$(?:\{[^}\n]*\}|[A-Za-z_]\w*|[0-9@#?*!$-])`), kind: Variable},
+	{pattern: re(`--?[A-Za-z0-9][\w-]*`), kind: Attribute, when: func(rest string, tokens []Token) bool {
+		return wordstart(tokens)
+	}},
+	{pattern: re("[^\\s;&|<>()'\"$#=\\\\`]+"), classify: func(match, rest string, tokens []Token) Kind {
+		switch {
+		case strings.HasPrefix(rest, "=") && command(tokens):
+			return Variable
+		case shkeywords[match] && command(tokens):
+			return Keyword
+		case shbuiltins[match] && command(tokens):
+			return Builtin
+		case command(tokens):
+			return Function
+		}
+		return Text
+	}},
+	{pattern: re(`&&|\|\||;;|>>|<<|[|&;<>]`), kind: Operator},
+	{pattern: re(`[(){}\[\]=]`), kind: Punctuation},
+}
+
+// YAML
+
+var yamlconstants = words("true false True False TRUE FALSE yes no on off null Null NULL ~")
+
+// key reports whether a key is followed by the mapping indicator.
+func key(rest string) bool {
+	rest = strings.TrimLeft(rest, " \t")
+	return strings.HasPrefix(rest, ":") && (len(rest) == 1 || strings.ContainsAny(rest[1:2], " \t\n"))
+}
+
+var yaml = rules{
+	whitespace,
+	{pattern: re(`#[^\n]*`), kind: Comment, when: func(rest string, tokens []Token) bool {
+		return wordstart(tokens)
+	}},
+	{pattern: re(`(?:---|\.\.\.)[ \t]*(?:\n|$)`), kind: Punctuation, when: func(rest string, tokens []Token) bool {
+		return linestart(tokens)
+	}},
+	{pattern: re(`"(?:\\.|[^"\\])*"|'(?:''|[^'])*'`), classify: func(match, rest string, tokens []Token) Kind {
+		if key(rest) {
+			return Property
+		}
+		return String
+	}},
+	{pattern: re(`[&*][\w-]+`), kind: Variable},
+	{pattern: re(`!!?[\w/-]*`), kind: Type},
+	{pattern: re(`[|>][+-]?\d*[ \t]*(?:\n|$)`), kind: Operator},
+	{pattern: re(`-(?:[ \t]|\n|$)`), kind: Punctuation},
+	{pattern: re(`[^\s#:,\[\]{}"'][^\n#:,\[\]{}]*`), classify: func(match, rest string, tokens []Token) Kind {
+		value := strings.TrimSpace(match)
+		switch {
+		case key(rest):
+			return Property
+		case yamlconstants[value]:
+			return Constant
+		case yamlnumber.MatchString(value):
+			return Number
+		}
+		return String
+	}},
+	{pattern: re(`[:\[\]{},?]`), kind: Punctuation},
+}
+
+var yamlnumber = re(`[-+]?(?:\d[\d_]*(?:\.\d*)?(?:[eE][-+]?\d+)?|0x[\da-fA-F]+|0o[0-7]+|\.inf|\.nan)$`)
+
+// HTML
+
+var (
+	htmltag       = re(`</?[A-Za-z][\w:-]*`)
+	htmlattribute = re(`[^\s"'>/=]+`)
+	htmlvalue     = re(`"[^"]*"?|'[^']*'?|[^\s>]+`)
+	htmlentity    = re(`&(?:#\d+|#[xX][\da-fA-F]+|\w+);`)
+)
+
+// lexhtml splits HTML into tokens, the contents of <script> and <style> elements are
+// split with the JavaScript and CSS rules.
+func lexhtml(code string) []Token {
+	tokens := []Token{}
+	for pos := 0; pos < len(code); {
+		rest := code[pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 3
+			}
+			tokens = appendtoken(tokens, Comment, rest[:end])
+			pos += end
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>') + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			tokens = appendtoken(tokens, Keyword, rest[:end])
+			pos += end
+		case htmltag.MatchString(rest):
+			name := htmltag.FindString(rest)
+			tokens = appendtoken(tokens, Punctuation, name[:len(name)-len(strings.TrimLeft(name, "</"))])
+			tokens = appendtoken(tokens, Tag, strings.TrimLeft(name, "</"))
+			pos += len(name)
+			pos += lextag(code[pos:], &tokens)
+
+			tag := strings.ToLower(strings.TrimLeft(name, "<"))
+			if tag != "script" && tag != "style" {
+				continue
+			}
+			body := code[pos:]
+			end := strings.Index(strings.ToLower(body), "</"+tag)
+			if end < 0 {
+				end = len(body)
+			}
+			if tag == "script" {
+				tokens = append(tokens, javascript.lex(body[:end])...)
+			} else {
+				tokens = append(tokens, css.lex(body[:end])...)
+			}
+			pos += end
+		case htmlentity.MatchString(rest):
+			entity := htmlentity.FindString(rest)
+			tokens = appendtoken(tokens, Constant, entity)
+			pos += len(entity)
+		default:
+			end := strings.IndexAny(rest[1:], "<&") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			tokens = appendtoken(tokens, Text, rest[:end])
+			pos += end
+		}
+	}
+	return tokens
+}
+
+// lextag splits the attributes of a tag up to its end and returns the length of the tag.
+func lextag(rest string, tokens *[]Token) int {
+	pos := 0
+	for pos < len(rest) {
+		s := rest[pos:]
+		switch {
+		case s[0] == '>':
+			*tokens = appendtoken(*tokens, Punctuation, ">")
+			return pos + 1
+		case strings.HasPrefix(s, "/>"):
+			*tokens = appendtoken(*tokens, Punctuation, "/>")
+			return pos + 2
+		case strings.ContainsRune(" \t\r\n", rune(s[0])):
+			end := len(s) - len(strings.TrimLeft(s, " \t\r\n"))
+			*tokens = appendtoken(*tokens, Text, s[:end])
+			pos += end
+		case s[0] == '=':
+			*tokens = appendtoken(*tokens, Operator, "=")
+			pos++
+			value := htmlvalue.FindString(rest[pos:])
+			if len(value) > 0 {
+				*tokens = appendtoken(*tokens, String, value)
+				pos += len(value)
+			}
+		case htmlattribute.MatchString(s):
+			name := htmlattribute.FindString(s)
+			*tokens = appendtoken(*tokens, Attribute, name)
+			pos += len(name)
+		default:
+			*tokens = appendtoken(*tokens, Text, s[:1])
+			pos++
+		}
+	}
+	return pos
+}
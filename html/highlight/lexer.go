@@ -0,0 +1,159 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a token, it decides the theme class the token is rendered with.
+type Kind int
+
+const (
+	Text = Kind(iota)
+	Keyword
+	Type
+	Builtin
+	Function
+	String
+	Number
+	Constant
+	Comment
+	Operator
+	Punctuation
+	Tag
+	Attribute
+	Property
+	Variable
+)
+
+var classes = [...]string{
+	Text:        "",
+	Keyword:     "hl-keyword",
+	Type:        "hl-type",
+	Builtin:     "hl-builtin",
+	Function:    "hl-function",
+	String:      "hl-string",
+	Number:      "hl-number",
+	Constant:    "hl-constant",
+	Comment:     "hl-comment",
+	Operator:    "hl-operator",
+	Punctuation: "hl-punctuation",
+	Tag:         "hl-tag",
+	Attribute:   "hl-attribute",
+	Property:    "hl-property",
+	Variable:    "hl-variable",
+}
+
+// Class returns the theme class of the kind, e.g. "hl-keyword". Text has no class.
+func (k Kind) Class() string {
+	if int(k) < len(classes) {
+		return classes[k]
+	}
+	return ""
+}
+
+// Token is a piece of highlighted code.
+type Token struct {
+	Kind Kind
+	Text string
+}
+
+// rule matches a token at the start of the rest of the code.
+type rule struct {
+	pattern *regexp.Regexp
+	kind    Kind
+	// when reports whether the rule applies at this position, e.g. regular expression
+	// literals of JavaScript only appear where an operand is expected
+	when func(rest string, tokens []Token) bool
+	// classify refines the kind of a match, e.g. keywords among identifiers
+	classify func(match, rest string, tokens []Token) Kind
+}
+
+type rules []rule
+
+// lex splits the code into tokens with the first rule that matches at each position,
+// the characters that no rule matches are text.
+func (rs rules) lex(code string) []Token {
+	tokens := []Token{}
+	for pos := 0; pos < len(code); {
+		rest := code[pos:]
+		matched := false
+		for _, r := range rs {
+			if r.when != nil && !r.when(rest, tokens) {
+				continue
+			}
+			loc := r.pattern.FindStringIndex(rest)
+			if loc == nil || loc[1] == 0 {
+				continue
+			}
+			match := rest[:loc[1]]
+			kind := r.kind
+			if r.classify != nil {
+				kind = r.classify(match, rest[loc[1]:], tokens)
+			}
+			tokens = appendtoken(tokens, kind, match)
+			pos += loc[1]
+			matched = true
+			break
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(rest)
+			tokens = appendtoken(tokens, Text, rest[:size])
+			pos += size
+		}
+	}
+	return tokens
+}
+
+// appendtoken appends a token, merging it with the previous one if they are of the same kind.
+func appendtoken(tokens []Token, kind Kind, text string) []Token {
+	if n := len(tokens); n > 0 && tokens[n-1].Kind == kind && (kind == Text || kind == Comment) {
+		tokens[n-1].Text += text
+		return tokens
+	}
+	return append(tokens, Token{Kind: kind, Text: text})
+}
+
+// significant returns the last token that is not whitespace or a comment.
+func significant(tokens []Token) (Token, bool) {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := tokens[i]
+		if token.Kind == Comment || (token.Kind == Text && len(strings.TrimSpace(token.Text)) == 0) {
+			continue
+		}
+		return token, true
+	}
+	return Token{}, false
+}
+
+// linestart reports whether the position follows a line break or the start of the code.
+func linestart(tokens []Token) bool {
+	return len(tokens) == 0 || strings.HasSuffix(tokens[len(tokens)-1].Text, "\n")
+}
+
+// wordstart reports whether the position follows whitespace or the start of the code.
+func wordstart(tokens []Token) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	last := tokens[len(tokens)-1].Text
+	return strings.ContainsAny(last[len(last)-1:], " \t\n;|&(")
+}
+
+func words(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(s) {
+		set[word] = true
+	}
+	return set
+}
+
+// call reports whether the rest of the code starts with a call, e.g. "(x)" after a function name.
+func call(rest string) bool {
+	return strings.HasPrefix(strings.TrimLeft(rest, " \t"), "(")
+}
+
+func re(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:` + pattern + `)`)
+}
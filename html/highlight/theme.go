@@ -0,0 +1,70 @@
+package highlight
+
+// Theme is a stylesheet for the classes of the highlighted code with light colors, and
+// dark colors within elements with the "dark" class. Colors can be overridden with the
+// --hl-* custom properties.
+//
+// Usage:
+//
+//	html.Head(html.Style(html.RawUnsafe(highlight.Theme)))
+const Theme = `.hl {
+  --hl-foreground: #24292f;
+  --hl-keyword: #cf222e;
+  --hl-type: #953800;
+  --hl-builtin: #0550ae;
+  --hl-function: #8250df;
+  --hl-string: #0a3069;
+  --hl-number: #0550ae;
+  --hl-constant: #0550ae;
+  --hl-comment: #6e7781;
+  --hl-operator: #cf222e;
+  --hl-punctuation: #57606a;
+  --hl-tag: #116329;
+  --hl-attribute: #0550ae;
+  --hl-property: #0550ae;
+  --hl-variable: #953800;
+  --hl-highlighted: rgba(234, 238, 242, 0.8);
+  --hl-added: rgba(46, 160, 67, 0.15);
+  --hl-removed: rgba(248, 81, 73, 0.15);
+  color: var(--hl-foreground);
+}
+.dark .hl {
+  --hl-foreground: #c9d1d9;
+  --hl-keyword: #ff7b72;
+  --hl-type: #ffa657;
+  --hl-builtin: #79c0ff;
+  --hl-function: #d2a8ff;
+  --hl-string: #a5d6ff;
+  --hl-number: #79c0ff;
+  --hl-constant: #79c0ff;
+  --hl-comment: #8b949e;
+  --hl-operator: #ff7b72;
+  --hl-punctuation: #8b949e;
+  --hl-tag: #7ee787;
+  --hl-attribute: #79c0ff;
+  --hl-property: #79c0ff;
+  --hl-variable: #ffa657;
+  --hl-highlighted: rgba(110, 118, 129, 0.2);
+}
+.hl-line { display: inline-block; min-width: 100%; }
+.hl-highlighted { background: var(--hl-highlighted); }
+.hl-added { background: var(--hl-added); }
+.hl-removed { background: var(--hl-removed); }
+.hl-number-line, .hl-marker { display: inline-block; user-select: none; color: var(--hl-comment); }
+.hl-number-line { width: 2.5em; padding-right: 1em; text-align: right; }
+.hl-marker { width: 1.5em; }
+.hl-keyword { color: var(--hl-keyword); }
+.hl-type { color: var(--hl-type); }
+.hl-builtin { color: var(--hl-builtin); }
+.hl-function { color: var(--hl-function); }
+.hl-string { color: var(--hl-string); }
+.hl-number { color: var(--hl-number); }
+.hl-constant { color: var(--hl-constant); }
+.hl-comment { color: var(--hl-comment); font-style: italic; }
+.hl-operator { color: var(--hl-operator); }
+.hl-punctuation { color: var(--hl-punctuation); }
+.hl-tag { color: var(--hl-tag); }
+.hl-attribute { color: var(--hl-attribute); }
+.hl-property { color: var(--hl-property); }
+.hl-variable { color: var(--hl-variable); }
+`
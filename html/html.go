@@ -11,7 +11,7 @@ The doctype is case-insensitive. The convention of MDN code examples is to use l
 
 https://developer.mozilla.org/en-US/docs/Glossary/Doctype
 */
-var Doctype = VoidEl("!DOCTYPE", Attr("html", ""))
+var Doctype = VoidEl("!DOCTYPE", Attr("html", "")).Freeze()
 
 /*
 # The Anchor element
@@ -0,0 +1,270 @@
+// Package htmltest provides utilities for testing html nodes. Nodes are rendered, parsed back
+// into a queryable tree and inspected with assertions that do not depend on the exact markup.
+//
+// Example usage:
+//
+//	func TestButton(t *testing.T) {
+//		doc := htmltest.Render(t, Button(html.Text("Save")))
+//
+//		doc.HasElement("button[data-slot=button]")
+//		doc.AttrEquals("button", "type", "submit")
+//		doc.TextContains("button", "Save")
+//		assert.NotNil(t, doc.ByRole("button", "Save"))
+//		doc.Accessible()
+//		doc.MatchSnapshot("button")
+//	}
+//
+// Snapshots are stored in the testdata directory of the package under test and are
+// written instead of compared when the tests are run with the -htmltest.update flag.
+package htmltest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/a11y"
+	"github.com/stretchr/testify/assert"
+)
+
+// The flag is namespaced so that it does not clash with the -update flags of the packages under test
+var update = flag.Bool("htmltest.update", false, "update the golden snapshot files of htmltest")
+
+// Document is a rendered and parsed node.
+type Document struct {
+	t      testing.TB
+	root   *html.Element
+	source string
+}
+
+// Render renders the given node with a background context, see RenderContext.
+func Render(t testing.TB, node html.Node) *Document {
+	t.Helper()
+	return RenderContext(t, context.Background(), node)
+}
+
+// RenderContext renders the given node as a single response, resolving the dynamic parts
+// (e.g. components) with ctx, and parses the result into a Document. The test fails immediately if the node cannot be rendered.
+func RenderContext(t testing.TB, ctx context.Context, node html.Node) *Document {
+	t.Helper()
+
+	ctx = html.WithResponseScope(ctx)
+	cw := html.NewChunkWriter()
+	if err := node.Render(cw); err != nil {
+		t.Fatalf("htmltest: failed to render node: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, ctx, buf); err != nil {
+			t.Fatalf("htmltest: failed to render node: %v", err)
+		}
+	}
+	source := string(html.ResolveHoisted(ctx, buf.Bytes()))
+
+	nodes, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("htmltest: failed to parse rendered markup: %v", err)
+	}
+	root := html.El("htmltest-root")
+	for _, node := range nodes {
+		root.AppendNode(node)
+	}
+	return &Document{t: t, root: root, source: source}
+}
+
+// String returns the rendered markup.
+func (d *Document) String() string {
+	return d.source
+}
+
+func (d *Document) compile(selector string) *html.Selector {
+	d.t.Helper()
+	sel, err := html.CompileSelector(selector)
+	if err != nil {
+		d.t.Fatalf("htmltest: %v", err)
+	}
+	return sel
+}
+
+// Query returns the first element that matches the selector or nil.
+func (d *Document) Query(selector string) *html.Element {
+	d.t.Helper()
+	return d.root.QuerySelectorCompiled(d.compile(selector))
+}
+
+// QueryAll returns every element that matches the selector.
+func (d *Document) QueryAll(selector string) []*html.Element {
+	d.t.Helper()
+	return d.root.QuerySelectorAllCompiled(d.compile(selector))
+}
+
+// HasElement asserts that at least one element matches the selector.
+func (d *Document) HasElement(selector string) bool {
+	d.t.Helper()
+	if d.Query(selector) == nil {
+		d.t.Errorf("htmltest: no element matches %q in:\n%s", selector, d.source)
+		return false
+	}
+	return true
+}
+
+// NoElement asserts that no element matches the selector.
+func (d *Document) NoElement(selector string) bool {
+	d.t.Helper()
+	if d.Query(selector) != nil {
+		d.t.Errorf("htmltest: an element matches %q in:\n%s", selector, d.source)
+		return false
+	}
+	return true
+}
+
+// AttrEquals asserts that the first element that matches the selector has the attribute with the given value.
+func (d *Document) AttrEquals(selector, key, value string) bool {
+	d.t.Helper()
+	el := d.Query(selector)
+	if el == nil {
+		d.t.Errorf("htmltest: no element matches %q in:\n%s", selector, d.source)
+		return false
+	}
+	for k, v := range el.Attributes() {
+		if k == key {
+			if v != value {
+				d.t.Errorf("htmltest: attribute %q of %q is %q, expected %q", key, selector, v, value)
+				return false
+			}
+			return true
+		}
+	}
+	d.t.Errorf("htmltest: %q has no attribute %q, expected %q", selector, key, value)
+	return false
+}
+
+// TextContains asserts that the text content of the first element that matches the selector contains substr.
+// Whitespace is collapsed before the text is compared.
+func (d *Document) TextContains(selector, substr string) bool {
+	d.t.Helper()
+	el := d.Query(selector)
+	if el == nil {
+		d.t.Errorf("htmltest: no element matches %q in:\n%s", selector, d.source)
+		return false
+	}
+	text := Text(el)
+	if !strings.Contains(text, normalize(substr)) {
+		d.t.Errorf("htmltest: text of %q is %q, expected it to contain %q", selector, text, substr)
+		return false
+	}
+	return true
+}
+
+// ByRole returns the first visible element with the given ARIA role, either set explicitly
+// with the role attribute or implied by the element (e.g. <button>, <a href>, <h1>), whose
+// accessible name equals name. An empty name matches any element with the role.
+// It returns nil if no element matches.
+func (d *Document) ByRole(role, name string) *html.Element {
+	d.t.Helper()
+	els := d.AllByRole(role, name)
+	if len(els) == 0 {
+		return nil
+	}
+	return els[0]
+}
+
+// AllByRole returns every visible element with the given role and accessible name, see ByRole.
+func (d *Document) AllByRole(role, name string) []*html.Element {
+	d.t.Helper()
+	name = normalize(name)
+	matches := []*html.Element{}
+	namer := a11y.NewNamer(d.root)
+	html.Walk(d.root, func(node html.Node) error {
+		el, ok := node.(*html.Element)
+		if !ok {
+			return nil
+		}
+		if a11y.Hidden(el) {
+			return html.SkipChildren
+		}
+		if a11y.Role(el) == role && (len(name) == 0 || namer.Name(el) == name) {
+			matches = append(matches, el)
+		}
+		return nil
+	})
+	return matches
+}
+
+// Accessible asserts that the document has no accessibility issues, e.g. images without
+// alt text or buttons without names, see the a11y package.
+func (d *Document) Accessible() bool {
+	d.t.Helper()
+	issues := a11y.Lint(d.root.GetNodes()...)
+	for _, issue := range issues {
+		d.t.Errorf("htmltest: %s", issue)
+	}
+	return len(issues) == 0
+}
+
+// MatchSnapshot asserts that the pretty printed markup of the document equals the
+// contents of testdata/<name>.golden. The file is written when the tests are run with -htmltest.update.
+func (d *Document) MatchSnapshot(name string) bool {
+	d.t.Helper()
+
+	cw := html.NewChunkWriterWithMode(html.ModePretty)
+	for _, node := range d.root.GetNodes() {
+		if err := node.Render(cw); err != nil {
+			d.t.Fatalf("htmltest: failed to render snapshot: %v", err)
+		}
+	}
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, context.Background(), buf); err != nil {
+			d.t.Fatalf("htmltest: failed to render snapshot: %v", err)
+		}
+	}
+	buf.WriteByte('\n')
+	actual := buf.String()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			d.t.Fatalf("htmltest: failed to create snapshot directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			d.t.Fatalf("htmltest: failed to write snapshot: %v", err)
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		d.t.Errorf("htmltest: failed to read snapshot, run the tests with -htmltest.update to create it: %v", err)
+		return false
+	}
+	return assert.Equal(d.t, string(expected), actual, "htmltest: snapshot %q does not match", name)
+}
+
+// Text returns the text content of the element with collapsed whitespace. The contents
+// of <script>, <style> and <template> elements are not included.
+func Text(el *html.Element) string {
+	b := new(strings.Builder)
+	html.Walk(el, func(node html.Node) error {
+		switch node := node.(type) {
+		case *html.Element:
+			switch node.Tag() {
+			case "script", "style", "template":
+				return html.SkipChildren
+			}
+		case html.Text:
+			b.WriteString(string(node))
+		}
+		return nil
+	})
+	return normalize(b.String())
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,95 @@
+package htmltest_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/htmltest"
+	"github.com/stretchr/testify/assert"
+)
+
+type userkey struct{}
+
+// Packages under test commonly define their own -update flag
+var _ = flag.Bool("update", false, "update the golden files")
+
+func form() html.Node {
+	return html.Form(
+		html.Class("login"),
+
+		html.Component(func(ctx context.Context) html.Node {
+			return html.H2(html.Textf("Welcome back, %s", ctx.Value(userkey{})))
+		}),
+		html.Label(html.For("email"), html.Text("Email address")),
+		html.Input(html.ID("email"), html.Type("email")),
+		html.Label(html.Input(html.Type("checkbox")), html.Text(" Remember me")),
+		html.Button(html.Type("submit"), html.Text("Sign in")),
+		html.Button(html.Aria("label", "Close"), html.Span(html.Aria("hidden", "true"), html.Text("×"))),
+		html.A(html.Href("/reset"), html.Text("Forgot  your password?")),
+	)
+}
+
+func TestDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.WithValue(context.Background(), userkey{}, "Jane")
+	doc := htmltest.RenderContext(t, ctx, form())
+
+	assert.True(doc.HasElement("form.login > button[type=submit]"))
+	assert.True(doc.NoElement("table"))
+	assert.True(doc.AttrEquals("input#email", "type", "email"))
+	assert.True(doc.TextContains("h2", "Welcome back, Jane"))
+	assert.True(doc.TextContains("a", "Forgot your password"))
+
+	assert.NotNil(doc.ByRole("heading", "Welcome back, Jane"))
+	assert.NotNil(doc.ByRole("button", "Sign in"))
+	assert.NotNil(doc.ByRole("button", "Close"))
+	assert.NotNil(doc.ByRole("textbox", "Email address"))
+	assert.NotNil(doc.ByRole("checkbox", "Remember me"))
+	assert.NotNil(doc.ByRole("link", "Forgot your password?"))
+	assert.Nil(doc.ByRole("button", "Cancel"))
+	assert.Len(doc.AllByRole("button", ""), 2)
+	assert.True(doc.Accessible())
+
+	doc.MatchSnapshot("form")
+}
+
+// fakeT records the failures of the assertions instead of failing the test, it implements
+// the subset of testing.TB the document uses. Fatalf does not stop the caller.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (*fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+}
+
+func (t *fakeT) Failed() bool {
+	return len(t.errors) > 0
+}
+
+func TestFailures(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := &fakeT{}
+	doc := htmltest.Render(fake, html.Div(html.ID("card")))
+	assert.False(doc.HasElement("span"))
+	assert.False(doc.AttrEquals("#card", "id", "other"))
+	assert.False(doc.TextContains("#card", "text"))
+	assert.Len(fake.errors, 3)
+
+	fake = &fakeT{}
+	assert.False(htmltest.Render(fake, html.Img(html.Src("/logo.png"))).Accessible())
+	assert.True(fake.Failed())
+	assert.Contains(fake.errors[0], "image-alt")
+}
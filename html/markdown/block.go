@@ -0,0 +1,862 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type blockkind int
+
+const (
+	kinddocument = blockkind(iota)
+	kindblockquote
+	kindlist
+	kinditem
+	kindparagraph
+	kindheading
+	kindthematicbreak
+	kindcodeblock
+	kindhtmlblock
+	kindtable
+	kindfootnote
+)
+
+type listdata struct {
+	ordered bool
+	// Marker character of bullet lists, or the delimiter (. or )) of ordered lists
+	marker byte
+	start  int
+	tight  bool
+	// Column of the marker and width of the marker with the spaces that follow it
+	markeroffset int
+	padding      int
+}
+
+type block struct {
+	kind     blockkind
+	parent   *block
+	children []*block
+	open     bool
+	// Whether the last line of the block was blank, used to decide if a list is loose
+	lastlineblank bool
+	startline     int
+
+	// Raw content of leaf blocks
+	content []byte
+	// Parsed content of paragraphs and headings
+	inlines *inline
+
+	// Headings
+	level int
+	id    string
+
+	// Code blocks
+	fenced      bool
+	fencechar   byte
+	fencelength int
+	fenceoffset int
+	info        string
+	literal     string
+
+	// HTML blocks
+	htmltype int
+
+	// Lists and list items
+	list *listdata
+	// 0 for regular items, 1 for unchecked and 2 for checked task list items
+	task int
+
+	// Tables, the first row is the header
+	aligns []string
+	rows   [][]*inline
+
+	// Footnote definitions
+	label string
+}
+
+func (b *block) lastchild() *block {
+	if len(b.children) == 0 {
+		return nil
+	}
+	return b.children[len(b.children)-1]
+}
+
+func (b *block) append(child *block) {
+	child.parent = b
+	b.children = append(b.children, child)
+}
+
+// replace puts the given block in the place of b in its parent.
+func (b *block) replace(with *block) {
+	with.parent = b.parent
+	for i, child := range b.parent.children {
+		if child == b {
+			b.parent.children[i] = with
+			return
+		}
+	}
+}
+
+func (b *block) unlink() {
+	for i, child := range b.parent.children {
+		if child == b {
+			b.parent.children = append(b.parent.children[:i], b.parent.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// cancontain reports whether a block of the given kind can be a child of b.
+func (b *block) cancontain(kind blockkind) bool {
+	switch b.kind {
+	case kinddocument, kindblockquote, kinditem, kindfootnote:
+		return kind != kinditem
+	case kindlist:
+		return kind == kinditem
+	default:
+		return false
+	}
+}
+
+// acceptslines reports whether the lines of the block are added to its content.
+func (b *block) acceptslines() bool {
+	switch b.kind {
+	case kindparagraph, kindcodeblock, kindhtmlblock, kindtable:
+		return true
+	default:
+		return false
+	}
+}
+
+type reference struct {
+	dest  string
+	title string
+}
+
+// parser is a port of the block parsing strategy of the CommonMark reference implementation,
+// see https://spec.commonmark.org/0.31.2/#appendix-a-parsing-strategy
+type parser struct {
+	doc    *block
+	tip    *block
+	oldtip *block
+
+	line   string
+	lineno int
+	// Byte offset and column of the parser in the current line
+	offset int
+	column int
+
+	nextnonspace       int
+	nextnonspacecolumn int
+	indent             int
+	indented           bool
+	blank              bool
+	partiallyconsumed  bool
+	allclosed          bool
+	lastmatched        *block
+
+	refs      map[string]reference
+	footnotes map[string]*block
+}
+
+func newparser() *parser {
+	doc := &block{kind: kinddocument, open: true}
+	return &parser{
+		doc:       doc,
+		tip:       doc,
+		oldtip:    doc,
+		allclosed: true,
+		refs:      map[string]reference{},
+		footnotes: map[string]*block{},
+	}
+}
+
+// parse parses the block structure of the source, the inline content is parsed separately.
+func (p *parser) parse(source string) *block {
+	source = strings.ReplaceAll(source, "\x00", "�")
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.TrimSuffix(source, "\n")
+
+	if len(source) > 0 {
+		for line := range strings.SplitSeq(source, "\n") {
+			p.incorporate(line)
+		}
+	}
+	for p.tip != nil {
+		p.finalize(p.tip)
+	}
+	return p.doc
+}
+
+func peek(s string, i int) int {
+	if i < len(s) {
+		return int(s[i])
+	}
+	return -1
+}
+
+func isspaceortab(c int) bool {
+	return c == ' ' || c == '\t'
+}
+
+func (p *parser) findnextnonspace() {
+	i := p.offset
+	column := p.column
+	for i < len(p.line) {
+		c := p.line[i]
+		if c == ' ' {
+			i++
+			column++
+		} else if c == '\t' {
+			i++
+			column += 4 - column%4
+		} else {
+			break
+		}
+	}
+	p.blank = i >= len(p.line)
+	p.nextnonspace = i
+	p.nextnonspacecolumn = column
+	p.indent = column - p.column
+	p.indented = p.indent >= 4
+}
+
+func (p *parser) advancenextnonspace() {
+	p.offset = p.nextnonspace
+	p.column = p.nextnonspacecolumn
+	p.partiallyconsumed = false
+}
+
+// advanceoffset advances the parser by count bytes, or count columns where tabs
+// count as the number of columns to the next tab stop.
+func (p *parser) advanceoffset(count int, columns bool) {
+	for count > 0 && p.offset < len(p.line) {
+		if p.line[p.offset] == '\t' {
+			totab := 4 - p.column%4
+			if columns {
+				p.partiallyconsumed = totab > count
+				advance := min(totab, count)
+				p.column += advance
+				if !p.partiallyconsumed {
+					p.offset++
+				}
+				count -= advance
+			} else {
+				p.partiallyconsumed = false
+				p.column += totab
+				p.offset++
+				count--
+			}
+		} else {
+			p.partiallyconsumed = false
+			p.offset++
+			p.column++
+			count--
+		}
+	}
+}
+
+// addline adds the rest of the current line to the content of the tip.
+func (p *parser) addline() {
+	if p.partiallyconsumed {
+		// Skip over the tab and add the columns that were not consumed as spaces
+		p.offset++
+		totab := 4 - p.column%4
+		p.tip.content = append(p.tip.content, strings.Repeat(" ", totab)...)
+	}
+	if p.offset < len(p.line) {
+		p.tip.content = append(p.tip.content, p.line[p.offset:]...)
+	}
+	p.tip.content = append(p.tip.content, '\n')
+}
+
+func (p *parser) addchild(kind blockkind) *block {
+	for !p.tip.cancontain(kind) {
+		p.finalize(p.tip)
+	}
+	child := &block{kind: kind, open: true, startline: p.lineno}
+	p.tip.append(child)
+	p.tip = child
+	return child
+}
+
+func (p *parser) closeunmatched() {
+	if p.allclosed {
+		return
+	}
+	for p.oldtip != p.lastmatched {
+		parent := p.oldtip.parent
+		p.finalize(p.oldtip)
+		p.oldtip = parent
+	}
+	p.allclosed = true
+}
+
+const (
+	matched = iota
+	unmatched
+	// The line is consumed by the block, e.g. the closing fence of a code block
+	consumed
+)
+
+var reclosingfence = regexp.MustCompile("^(?:`{3,}|~{3,})[ \t]*$")
+
+// continues reports whether the block continues on the current line and consumes its markers.
+func (p *parser) continues(b *block) int {
+	switch b.kind {
+	case kinddocument, kindlist:
+		return matched
+	case kindblockquote:
+		if !p.indented && peek(p.line, p.nextnonspace) == '>' {
+			p.advancenextnonspace()
+			p.advanceoffset(1, false)
+			if isspaceortab(peek(p.line, p.offset)) {
+				p.advanceoffset(1, true)
+			}
+			return matched
+		}
+		return unmatched
+	case kinditem:
+		if p.blank {
+			if len(b.children) == 0 {
+				// A list item can begin with at most one blank line
+				return unmatched
+			}
+			p.advancenextnonspace()
+		} else if p.indent >= b.list.markeroffset+b.list.padding {
+			p.advanceoffset(b.list.markeroffset+b.list.padding, true)
+		} else {
+			return unmatched
+		}
+		return matched
+	case kindfootnote:
+		if p.blank {
+			p.advancenextnonspace()
+		} else if p.indent >= 4 {
+			p.advanceoffset(4, true)
+		} else {
+			return unmatched
+		}
+		return matched
+	case kindcodeblock:
+		if b.fenced {
+			rest := p.line[min(p.nextnonspace, len(p.line)):]
+			if !p.indented && peek(p.line, p.nextnonspace) == int(b.fencechar) && reclosingfence.MatchString(rest) &&
+				len(strings.TrimRight(rest, " \t")) >= b.fencelength {
+				p.finalize(b)
+				return consumed
+			}
+			for i := b.fenceoffset; i > 0 && isspaceortab(peek(p.line, p.offset)); i-- {
+				p.advanceoffset(1, true)
+			}
+			return matched
+		}
+		if p.indent >= 4 {
+			p.advanceoffset(4, true)
+		} else if p.blank {
+			p.advancenextnonspace()
+		} else {
+			return unmatched
+		}
+		return matched
+	case kindhtmlblock:
+		if p.blank && (b.htmltype == 6 || b.htmltype == 7) {
+			return unmatched
+		}
+		return matched
+	case kindparagraph, kindtable:
+		if p.blank {
+			return unmatched
+		}
+		return matched
+	default:
+		return unmatched
+	}
+}
+
+func (p *parser) incorporate(line string) {
+	container := p.doc
+	p.oldtip = p.tip
+	p.offset = 0
+	p.column = 0
+	p.blank = false
+	p.partiallyconsumed = false
+	p.lineno++
+	p.line = line
+
+	// Match the open blocks that continue on this line
+	for {
+		last := container.lastchild()
+		if last == nil || !last.open {
+			break
+		}
+		container = last
+		p.findnextnonspace()
+
+		result := p.continues(container)
+		if result == consumed {
+			return
+		}
+		if result == unmatched {
+			container = container.parent
+			break
+		}
+	}
+	p.allclosed = container == p.oldtip
+	p.lastmatched = container
+
+	// Look for the starts of new blocks
+	leaf := container.kind == kindcodeblock || container.kind == kindhtmlblock
+	for !leaf {
+		p.findnextnonspace()
+		started := false
+		for _, start := range blockstarts {
+			result := start(p, container)
+			if result == startnone {
+				continue
+			}
+			container = p.tip
+			leaf = result == startleaf
+			started = true
+			break
+		}
+		if !started {
+			p.advancenextnonspace()
+			break
+		}
+	}
+
+	if !p.allclosed && !p.blank && p.tip.kind == kindparagraph {
+		// Lazy paragraph continuation
+		p.addline()
+		return
+	}
+
+	p.closeunmatched()
+	if p.blank && container.lastchild() != nil {
+		container.lastchild().lastlineblank = true
+	}
+	lastlineblank := p.blank && !(container.kind == kindblockquote ||
+		(container.kind == kindcodeblock && container.fenced) ||
+		(container.kind == kinditem && len(container.children) == 0 && container.startline == p.lineno))
+	for b := container; b != nil; b = b.parent {
+		b.lastlineblank = lastlineblank
+	}
+
+	if container.acceptslines() {
+		p.addline()
+		if container.kind == kindhtmlblock && container.htmltype >= 1 && container.htmltype <= 5 &&
+			rehtmlblockclose[container.htmltype].MatchString(p.line[min(p.offset, len(p.line)):]) {
+			p.finalize(container)
+		}
+	} else if p.offset < len(p.line) && !p.blank {
+		p.addchild(kindparagraph)
+		p.advancenextnonspace()
+		p.addline()
+	}
+}
+
+const (
+	startnone = iota
+	startcontainer
+	startleaf
+)
+
+var (
+	reatxheading     = regexp.MustCompile(`^#{1,6}(?:[ \t]+|$)`)
+	reatxclosing     = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	resetextheading  = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+	rethematicbreak  = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:_[ \t]*){3,}|(?:-[ \t]*){3,})$`)
+	rebulletmarker   = regexp.MustCompile(`^[*+-]`)
+	reorderedmarker  = regexp.MustCompile(`^(\d{1,9})([.)])`)
+	refootnotelabel  = regexp.MustCompile(`^\[\^([^\]\s]+)\]:`)
+	retaskmarker     = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	retabledelimiter = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+var blockstarts = []func(p *parser, container *block) int{
+	// Block quotes
+	func(p *parser, container *block) int {
+		if p.indented || peek(p.line, p.nextnonspace) != '>' {
+			return startnone
+		}
+		p.advancenextnonspace()
+		p.advanceoffset(1, false)
+		if isspaceortab(peek(p.line, p.offset)) {
+			p.advanceoffset(1, true)
+		}
+		p.closeunmatched()
+		p.addchild(kindblockquote)
+		return startcontainer
+	},
+	// ATX headings
+	func(p *parser, container *block) int {
+		if p.indented {
+			return startnone
+		}
+		match := reatxheading.FindString(p.line[p.nextnonspace:])
+		if len(match) == 0 {
+			return startnone
+		}
+		p.advancenextnonspace()
+		p.advanceoffset(len(match), false)
+		p.closeunmatched()
+		heading := p.addchild(kindheading)
+		heading.level = len(strings.TrimRight(match, " \t"))
+		rest := p.line[min(p.offset, len(p.line)):]
+		heading.content = []byte(strings.TrimSpace(reatxclosing.ReplaceAllString(rest, "")))
+		p.advanceoffset(len(p.line)-p.offset, false)
+		return startleaf
+	},
+	// Fenced code blocks
+	func(p *parser, container *block) int {
+		if p.indented {
+			return startnone
+		}
+		rest := p.line[p.nextnonspace:]
+		c := peek(rest, 0)
+		if c != '`' && c != '~' {
+			return startnone
+		}
+		length := 0
+		for length < len(rest) && rest[length] == byte(c) {
+			length++
+		}
+		if length < 3 || (c == '`' && strings.IndexByte(rest[length:], '`') >= 0) {
+			return startnone
+		}
+		p.closeunmatched()
+		code := p.addchild(kindcodeblock)
+		code.fenced = true
+		code.fencechar = byte(c)
+		code.fencelength = length
+		code.fenceoffset = p.indent
+		p.advancenextnonspace()
+		p.advanceoffset(length, false)
+		return startleaf
+	},
+	// HTML blocks
+	func(p *parser, container *block) int {
+		if p.indented || peek(p.line, p.nextnonspace) != '<' {
+			return startnone
+		}
+		rest := p.line[p.nextnonspace:]
+		for kind := 1; kind <= 7; kind++ {
+			if !rehtmlblockopen[kind].MatchString(rest) {
+				continue
+			}
+			if kind == 7 && (container.kind == kindparagraph || (!p.allclosed && !p.blank && p.tip.kind == kindparagraph)) {
+				// Type 7 blocks can not interrupt a paragraph
+				return startnone
+			}
+			p.closeunmatched()
+			html := p.addchild(kindhtmlblock)
+			html.htmltype = kind
+			return startleaf
+		}
+		return startnone
+	},
+	// Tables, the last line of the paragraph becomes the header row
+	func(p *parser, container *block) int {
+		if p.indented || container.kind != kindparagraph {
+			return startnone
+		}
+		rest := p.line[p.nextnonspace:]
+		if !strings.Contains(rest, "|") || !retabledelimiter.MatchString(rest) {
+			return startnone
+		}
+		content := strings.TrimSuffix(string(container.content), "\n")
+		before, header := "", content
+		if i := strings.LastIndexByte(content, '\n'); i >= 0 {
+			before, header = content[:i+1], content[i+1:]
+		}
+		aligns := []string{}
+		for _, cell := range splitrow(rest) {
+			cell = strings.TrimSpace(cell)
+			switch {
+			case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+				aligns = append(aligns, "center")
+			case strings.HasPrefix(cell, ":"):
+				aligns = append(aligns, "left")
+			case strings.HasSuffix(cell, ":"):
+				aligns = append(aligns, "right")
+			default:
+				aligns = append(aligns, "")
+			}
+		}
+		if len(splitrow(header)) != len(aligns) {
+			return startnone
+		}
+
+		p.closeunmatched()
+		table := &block{kind: kindtable, open: true, startline: p.lineno, aligns: aligns, content: []byte(header + "\n")}
+		if len(before) > 0 {
+			container.content = []byte(before)
+			p.finalize(container)
+			p.tip.append(table)
+		} else {
+			container.replace(table)
+		}
+		p.tip = table
+		p.advanceoffset(len(p.line)-p.offset, false)
+		return startleaf
+	},
+	// Setext headings
+	func(p *parser, container *block) int {
+		if p.indented || container.kind != kindparagraph {
+			return startnone
+		}
+		match := resetextheading.FindString(p.line[p.nextnonspace:])
+		if len(match) == 0 {
+			return startnone
+		}
+		p.closeunmatched()
+		p.references(container)
+		if len(container.content) == 0 {
+			return startnone
+		}
+		heading := &block{kind: kindheading, open: true, startline: container.startline, content: container.content, level: 2}
+		if match[0] == '=' {
+			heading.level = 1
+		}
+		container.replace(heading)
+		p.tip = heading
+		p.advanceoffset(len(p.line)-p.offset, false)
+		return startleaf
+	},
+	// Thematic breaks
+	func(p *parser, container *block) int {
+		if p.indented || !rethematicbreak.MatchString(p.line[p.nextnonspace:]) {
+			return startnone
+		}
+		p.closeunmatched()
+		p.addchild(kindthematicbreak)
+		p.advanceoffset(len(p.line)-p.offset, false)
+		return startleaf
+	},
+	// Footnote definitions
+	func(p *parser, container *block) int {
+		if p.indented || container.kind == kindparagraph {
+			return startnone
+		}
+		match := refootnotelabel.FindStringSubmatch(p.line[p.nextnonspace:])
+		if match == nil {
+			return startnone
+		}
+		p.advancenextnonspace()
+		p.advanceoffset(len(match[0]), false)
+		p.closeunmatched()
+		footnote := p.addchild(kindfootnote)
+		footnote.label = normalizelabel(match[1])
+		return startcontainer
+	},
+	// List items
+	func(p *parser, container *block) int {
+		if p.indented && container.kind != kindlist {
+			return startnone
+		}
+		data := p.listmarker(container)
+		if data == nil {
+			return startnone
+		}
+		p.closeunmatched()
+		if p.tip.kind != kindlist || !p.tip.list.matches(data) {
+			list := p.addchild(kindlist)
+			list.list = &listdata{}
+			*list.list = *data
+		}
+		item := p.addchild(kinditem)
+		item.list = data
+		return startcontainer
+	},
+	// Indented code blocks
+	func(p *parser, container *block) int {
+		if !p.indented || p.tip.kind == kindparagraph || p.blank {
+			return startnone
+		}
+		p.advanceoffset(4, true)
+		p.closeunmatched()
+		p.addchild(kindcodeblock)
+		return startleaf
+	},
+}
+
+func (l *listdata) matches(other *listdata) bool {
+	return l.ordered == other.ordered && l.marker == other.marker
+}
+
+// listmarker parses the marker of a list item and consumes it with the spaces that follow it.
+func (p *parser) listmarker(container *block) *listdata {
+	if p.indent >= 4 {
+		return nil
+	}
+	rest := p.line[p.nextnonspace:]
+	data := &listdata{markeroffset: p.indent, tight: true}
+	length := 0
+	if match := rebulletmarker.FindString(rest); len(match) > 0 {
+		data.marker = match[0]
+		length = 1
+	} else if match := reorderedmarker.FindStringSubmatch(rest); match != nil && (container.kind != kindparagraph || match[1] == "1") {
+		data.ordered = true
+		data.start, _ = strconv.Atoi(match[1])
+		data.marker = match[2][0]
+		length = len(match[0])
+	} else {
+		return nil
+	}
+
+	next := peek(p.line, p.nextnonspace+length)
+	if next != -1 && next != ' ' && next != '\t' {
+		return nil
+	}
+	// A list item that interrupts a paragraph can not be empty
+	if container.kind == kindparagraph && len(strings.TrimLeft(p.line[p.nextnonspace+length:], " \t")) == 0 {
+		return nil
+	}
+
+	p.advancenextnonspace()
+	p.advanceoffset(length, true)
+	startcolumn := p.column
+	startoffset := p.offset
+	for {
+		p.advanceoffset(1, true)
+		next = peek(p.line, p.offset)
+		if p.column-startcolumn >= 5 || !isspaceortab(next) {
+			break
+		}
+	}
+	blankitem := peek(p.line, p.offset) == -1
+	spaces := p.column - startcolumn
+	if spaces >= 5 || spaces < 1 || blankitem {
+		data.padding = length + 1
+		p.column = startcolumn
+		p.offset = startoffset
+		p.partiallyconsumed = false
+		if isspaceortab(peek(p.line, p.offset)) {
+			p.advanceoffset(1, true)
+		}
+	} else {
+		data.padding = length + spaces
+	}
+	return data
+}
+
+// references parses the link reference definitions at the start of the paragraph.
+func (p *parser) references(b *block) {
+	content := string(b.content)
+	for strings.HasPrefix(content, "[") {
+		ip := &inlineparser{subject: content, refs: p.refs}
+		n := ip.reference()
+		if n == 0 {
+			break
+		}
+		content = content[n:]
+	}
+	if len(strings.TrimSpace(content)) == 0 {
+		content = ""
+	}
+	b.content = []byte(content)
+}
+
+var (
+	retrailingblanklines = regexp.MustCompile(`(?:\n[ \t]*)+$`)
+	rehtmltrailing       = regexp.MustCompile(`(?:\n[ \t]*)+$`)
+)
+
+func (p *parser) finalize(b *block) {
+	parent := b.parent
+	b.open = false
+
+	switch b.kind {
+	case kindparagraph:
+		p.references(b)
+		if len(b.content) == 0 {
+			b.unlink()
+		}
+	case kindcodeblock:
+		content := string(b.content)
+		if b.fenced {
+			first, rest, _ := strings.Cut(content, "\n")
+			b.info = strings.TrimSpace(unescape(first))
+			b.literal = rest
+		} else {
+			b.literal = retrailingblanklines.ReplaceAllString(content, "") + "\n"
+		}
+		b.content = nil
+	case kindhtmlblock:
+		b.literal = rehtmltrailing.ReplaceAllString(string(b.content), "")
+		b.content = nil
+	case kindlist:
+		b.list.tight = true
+		for i, item := range b.children {
+			last := i == len(b.children)-1
+			if !last && endswithblankline(item) {
+				b.list.tight = false
+				break
+			}
+			for j, child := range item.children {
+				if endswithblankline(child) && (!last || j < len(item.children)-1) {
+					b.list.tight = false
+					break
+				}
+			}
+			if !b.list.tight {
+				break
+			}
+		}
+	case kinditem:
+		// Task list items start with [ ] or [x] in their first paragraph
+		if len(b.children) > 0 && b.children[0].kind == kindparagraph {
+			first := b.children[0]
+			if match := retaskmarker.Find(first.content); match != nil && len(first.content) > len(match) {
+				if match[1] == ' ' {
+					b.task = 1
+				} else {
+					b.task = 2
+				}
+				first.content = first.content[len(match):]
+			}
+		}
+	case kindfootnote:
+		if _, ok := p.footnotes[b.label]; !ok {
+			p.footnotes[b.label] = b
+		}
+	}
+	p.tip = parent
+}
+
+func endswithblankline(b *block) bool {
+	for b != nil {
+		if b.lastlineblank {
+			return true
+		}
+		if b.kind != kindlist && b.kind != kinditem {
+			return false
+		}
+		b = b.lastchild()
+	}
+	return false
+}
+
+// splitrow splits a table row into its cells, escaped pipes do not separate cells.
+func splitrow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	cells := []string{}
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, row[start:i])
+			start = i + 1
+		}
+	}
+	return append(cells, row[start:])
+}
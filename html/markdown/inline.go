@@ -0,0 +1,887 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type inlinekind int
+
+const (
+	kindcontainer = inlinekind(iota)
+	kindtext
+	kindsoftbreak
+	kindlinebreak
+	kindcode
+	kindemphasis
+	kindstrong
+	kindstrikethrough
+	kindlink
+	kindimage
+	kindhtml
+	kindfootnoteref
+)
+
+// inline is a node of the inline content of a block. Nodes are kept in doubly linked
+// lists so that the emphasis and link processing can move ranges of siblings around.
+type inline struct {
+	kind    inlinekind
+	literal string
+	dest    string
+	title   string
+	// Normalized label of footnote references
+	label string
+
+	parent, prev, next, first, last *inline
+}
+
+func (n *inline) appendchild(child *inline) {
+	child.unlink()
+	child.parent = n
+	if n.last != nil {
+		n.last.next = child
+		child.prev = n.last
+	} else {
+		n.first = child
+	}
+	n.last = child
+}
+
+func (n *inline) insertafter(sibling *inline) {
+	sibling.unlink()
+	sibling.next = n.next
+	if sibling.next != nil {
+		sibling.next.prev = sibling
+	}
+	sibling.prev = n
+	n.next = sibling
+	sibling.parent = n.parent
+	if sibling.next == nil && sibling.parent != nil {
+		sibling.parent.last = sibling
+	}
+}
+
+func (n *inline) unlink() {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else if n.parent != nil {
+		n.parent.first = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else if n.parent != nil {
+		n.parent.last = n.prev
+	}
+	n.parent, n.prev, n.next = nil, nil, nil
+}
+
+// text returns the plain text of the node and its descendants.
+func (n *inline) text() string {
+	b := new(strings.Builder)
+	var walk func(n *inline)
+	walk = func(n *inline) {
+		switch n.kind {
+		case kindtext, kindcode:
+			b.WriteString(n.literal)
+		case kindsoftbreak, kindlinebreak:
+			b.WriteByte(' ')
+		}
+		for child := n.first; child != nil; child = child.next {
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+type delimiter struct {
+	char       byte
+	count      int
+	origcount  int
+	node       *inline
+	prev, next *delimiter
+	canopen    bool
+	canclose   bool
+}
+
+type bracket struct {
+	node              *inline
+	prev              *bracket
+	previousdelimiter *delimiter
+	index             int
+	image             bool
+	active            bool
+	bracketafter      bool
+}
+
+type inlineparser struct {
+	subject    string
+	pos        int
+	delimiters *delimiter
+	brackets   *bracket
+	refs       map[string]reference
+	footnotes  map[string]*block
+}
+
+const (
+	escapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	entity    = `&(?:#[xX][a-fA-F0-9]{1,6}|#[0-9]{1,7}|[a-zA-Z][a-zA-Z0-9]{1,31});`
+
+	tagname       = `[A-Za-z][A-Za-z0-9-]*`
+	attributename = `[a-zA-Z_:][a-zA-Z0-9:._-]*`
+	attribute     = `(?:\s+` + attributename + `(?:\s*=\s*(?:[^"'=<>` + "`" + `\x00-\x20]+|'[^']*'|"[^"]*"))?)`
+	opentag       = `<` + tagname + attribute + `*\s*/?>`
+	closetag      = `</` + tagname + `\s*>`
+	htmltag       = `(?:` + opentag + `|` + closetag + `|<!-->|<!--->|<!--[\s\S]*?-->|[<][?][\s\S]*?[?][>]|<![A-Za-z]+[^>]*>|<!\[CDATA\[[\s\S]*?\]\]>)`
+)
+
+var (
+	reentity        = regexp.MustCompile(`^` + entity)
+	reunescape      = regexp.MustCompile(`\\[!"#$%&'()*+,./:;<=>?@[\\\]^_` + "`" + `{|}~-]|` + entity)
+	rehtmltag       = regexp.MustCompile(`^` + htmltag)
+	reautolink      = regexp.MustCompile(`^<[A-Za-z][A-Za-z0-9.+-]{1,31}:[^<>\x00-\x20]*>`)
+	reemailautolink = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+	relinklabel     = regexp.MustCompile(`^\[(?:[^\\\[\]]|\\[\s\S]){0,999}\]`)
+	relinktitle     = regexp.MustCompile(`^(?:"(?:\\[\s\S]|[^"\\\x00])*"|'(?:\\[\s\S]|[^'\\\x00])*'|\((?:\\[\s\S]|[^()\\\x00])*\))`)
+	relinkdest      = regexp.MustCompile(`^<(?:[^<>\n\\\x00]|\\.)*>`)
+	respnl          = regexp.MustCompile(`^ *(?:\n *)?`)
+	relineend       = regexp.MustCompile(`^[ \t]*(?:\n|$)`)
+	rewhitespace    = regexp.MustCompile(`[ \t\r\n]+`)
+	reextendedlink  = regexp.MustCompile(`(?:https?://|www\.)[^\s<]*`)
+
+	rehtmlblockopen = []*regexp.Regexp{
+		nil,
+		regexp.MustCompile(`(?i)^<(?:script|pre|textarea|style)(?:\s|>|$)`),
+		regexp.MustCompile(`^<!--`),
+		regexp.MustCompile(`^<[?]`),
+		regexp.MustCompile(`^<![A-Za-z]`),
+		regexp.MustCompile(`^<!\[CDATA\[`),
+		regexp.MustCompile(`(?i)^<[/]?(?:address|article|aside|base|basefont|blockquote|body|caption|center|col|colgroup|dd|details|dialog|dir|div|dl|dt|fieldset|figcaption|figure|footer|form|frame|frameset|h[123456]|head|header|hr|html|iframe|legend|li|link|main|menu|menuitem|nav|noframes|ol|optgroup|option|p|param|search|section|summary|table|tbody|td|tfoot|th|thead|title|tr|track|ul)(?:\s|[/]?[>]|$)`),
+		regexp.MustCompile(`(?i)^(?:` + opentag + `|` + closetag + `)\s*$`),
+	}
+	rehtmlblockclose = []*regexp.Regexp{
+		nil,
+		regexp.MustCompile(`(?i)</(?:script|pre|textarea|style)>`),
+		regexp.MustCompile(`-->`),
+		regexp.MustCompile(`\?>`),
+		regexp.MustCompile(`>`),
+		regexp.MustCompile(`\]\]>`),
+	}
+)
+
+// unescape resolves the backslash escapes and the entities of the string.
+func unescape(s string) string {
+	if !strings.ContainsAny(s, `\&`) {
+		return s
+	}
+	return reunescape.ReplaceAllStringFunc(s, func(match string) string {
+		if match[0] == '\\' {
+			return match[1:]
+		}
+		return html.UnescapeString(match)
+	})
+}
+
+// normalizelabel normalizes a link label for case insensitive matching.
+func normalizelabel(label string) string {
+	label = rewhitespace.ReplaceAllString(strings.TrimSpace(label), " ")
+	return strings.ToLower(strings.ToUpper(label))
+}
+
+// normalizeurl percent encodes the characters of the url that are not allowed in urls,
+// existing percent encoded sequences are kept.
+func normalizeurl(url string) string {
+	b := new(strings.Builder)
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		switch {
+		case c == '%' && i+2 < len(url) && ishex(url[i+1]) && ishex(url[i+2]):
+			b.WriteByte(c)
+		case c < 0x80 && (isalnum(c) || strings.IndexByte(";/?:@&=+$,-_.!~*'()#", c) >= 0):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func ishex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isalnum(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parse parses the given content into the children of the container node.
+func (ip *inlineparser) parse(content string) *inline {
+	container := &inline{kind: kindcontainer}
+	ip.subject = strings.TrimSpace(content)
+	ip.pos = 0
+	ip.delimiters = nil
+	ip.brackets = nil
+	for ip.pos < len(ip.subject) {
+		ip.parseinline(container)
+	}
+	ip.processemphasis(nil)
+	mergetext(container)
+	autolink(container)
+	return container
+}
+
+func (ip *inlineparser) peek() int {
+	return peek(ip.subject, ip.pos)
+}
+
+func text(s string) *inline {
+	return &inline{kind: kindtext, literal: s}
+}
+
+func (ip *inlineparser) parseinline(container *inline) {
+	handled := false
+	switch c := ip.peek(); c {
+	case '\n':
+		handled = ip.newline(container)
+	case '\\':
+		handled = ip.backslash(container)
+	case '`':
+		handled = ip.backticks(container)
+	case '*', '_', '~':
+		handled = ip.delimiter(container, byte(c))
+	case '[':
+		ip.addbracket(container, ip.pos, false)
+		ip.pos++
+		handled = true
+	case '!':
+		handled = ip.bang(container)
+	case ']':
+		handled = ip.closebracket(container)
+	case '<':
+		handled = ip.autolink(container) || ip.htmltag(container)
+	case '&':
+		handled = ip.entity(container)
+	default:
+		handled = ip.text(container)
+	}
+	if !handled {
+		container.appendchild(text(ip.subject[ip.pos : ip.pos+1]))
+		ip.pos++
+	}
+}
+
+func (ip *inlineparser) text(container *inline) bool {
+	end := ip.pos
+	for end < len(ip.subject) && strings.IndexByte("\n\\`*_~[]!<&", ip.subject[end]) < 0 {
+		end++
+	}
+	if end == ip.pos {
+		return false
+	}
+	container.appendchild(text(ip.subject[ip.pos:end]))
+	ip.pos = end
+	return true
+}
+
+func (ip *inlineparser) newline(container *inline) bool {
+	ip.pos++
+	last := container.last
+	if last != nil && last.kind == kindtext && strings.HasSuffix(last.literal, " ") {
+		hard := strings.HasSuffix(last.literal, "  ")
+		last.literal = strings.TrimRight(last.literal, " ")
+		if hard {
+			container.appendchild(&inline{kind: kindlinebreak})
+		} else {
+			container.appendchild(&inline{kind: kindsoftbreak})
+		}
+	} else {
+		container.appendchild(&inline{kind: kindsoftbreak})
+	}
+	// Leading spaces of the next line are ignored
+	for ip.pos < len(ip.subject) && ip.subject[ip.pos] == ' ' {
+		ip.pos++
+	}
+	return true
+}
+
+func (ip *inlineparser) backslash(container *inline) bool {
+	ip.pos++
+	c := ip.peek()
+	switch {
+	case c == '\n':
+		ip.pos++
+		container.appendchild(&inline{kind: kindlinebreak})
+	case c != -1 && strings.IndexByte(escapable, byte(c)) >= 0:
+		container.appendchild(text(string(rune(c))))
+		ip.pos++
+	default:
+		container.appendchild(text(`\`))
+	}
+	return true
+}
+
+func (ip *inlineparser) backticks(container *inline) bool {
+	start := ip.pos
+	for ip.pos < len(ip.subject) && ip.subject[ip.pos] == '`' {
+		ip.pos++
+	}
+	ticks := ip.subject[start:ip.pos]
+	after := ip.pos
+	for i := after; i < len(ip.subject); {
+		if ip.subject[i] != '`' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(ip.subject) && ip.subject[j] == '`' {
+			j++
+		}
+		if j-i == len(ticks) {
+			code := strings.ReplaceAll(ip.subject[after:i], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			container.appendchild(&inline{kind: kindcode, literal: code})
+			ip.pos = j
+			return true
+		}
+		i = j
+	}
+	// No matching backtick string, the backticks are literal
+	container.appendchild(text(ticks))
+	return true
+}
+
+// runeclass reports whether the rune is a whitespace or a punctuation character,
+// the start and the end of the subject count as whitespace.
+func runeclass(r rune, ok bool) (space, punct bool) {
+	if !ok {
+		return true, false
+	}
+	return unicode.IsSpace(r), unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func (ip *inlineparser) delimiter(container *inline, char byte) bool {
+	start := ip.pos
+	for ip.pos < len(ip.subject) && ip.subject[ip.pos] == char {
+		ip.pos++
+	}
+	count := ip.pos - start
+	node := text(ip.subject[start:ip.pos])
+	container.appendchild(node)
+	if char == '~' && count > 2 {
+		return true
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(ip.subject[:start])
+	after, _ := utf8.DecodeRuneInString(ip.subject[ip.pos:])
+	beforespace, beforepunct := runeclass(before, start > 0)
+	afterspace, afterpunct := runeclass(after, ip.pos < len(ip.subject))
+
+	left := !afterspace && (!afterpunct || beforespace || beforepunct)
+	right := !beforespace && (!beforepunct || afterspace || afterpunct)
+	var canopen, canclose bool
+	if char == '_' {
+		canopen = left && (!right || beforepunct)
+		canclose = right && (!left || afterpunct)
+	} else {
+		canopen = left
+		canclose = right
+	}
+
+	if canopen || canclose {
+		d := &delimiter{char: char, count: count, origcount: count, node: node, prev: ip.delimiters, canopen: canopen, canclose: canclose}
+		if d.prev != nil {
+			d.prev.next = d
+		}
+		ip.delimiters = d
+	}
+	return true
+}
+
+func (ip *inlineparser) removedelimiter(d *delimiter) {
+	if d.prev != nil {
+		d.prev.next = d.next
+	}
+	if d.next != nil {
+		d.next.prev = d.prev
+	} else {
+		ip.delimiters = d.prev
+	}
+}
+
+type openerkey struct {
+	char  byte
+	index int
+}
+
+// processemphasis matches the delimiters above the stack bottom into emphasis, strong
+// emphasis and strikethrough nodes, see https://spec.commonmark.org/0.31.2/#phase-2-inline-structure
+func (ip *inlineparser) processemphasis(bottom *delimiter) {
+	openersbottom := map[openerkey]*delimiter{}
+
+	closer := ip.delimiters
+	for closer != nil && closer.prev != bottom {
+		closer = closer.prev
+	}
+	for closer != nil {
+		if !closer.canclose {
+			closer = closer.next
+			continue
+		}
+		key := openerkey{char: closer.char, index: closer.count}
+		if closer.char != '~' {
+			key.index = closer.origcount % 3
+			if closer.canopen {
+				key.index += 3
+			}
+		}
+
+		opener := closer.prev
+		found := false
+		for opener != nil && opener != bottom && opener != openersbottom[key] {
+			if opener.char == closer.char && opener.canopen {
+				if closer.char == '~' {
+					found = opener.count == closer.count
+				} else {
+					odd := (closer.canopen || opener.canclose) && closer.origcount%3 != 0 && (opener.origcount+closer.origcount)%3 == 0
+					found = !odd
+				}
+				if found {
+					break
+				}
+			}
+			opener = opener.prev
+		}
+
+		oldcloser := closer
+		if found {
+			used := 1
+			kind := kindemphasis
+			switch {
+			case closer.char == '~':
+				used = closer.count
+				kind = kindstrikethrough
+			case closer.count >= 2 && opener.count >= 2:
+				used = 2
+				kind = kindstrong
+			}
+			openernode, closernode := opener.node, closer.node
+			opener.count -= used
+			closer.count -= used
+			openernode.literal = openernode.literal[:len(openernode.literal)-used]
+			closernode.literal = closernode.literal[:len(closernode.literal)-used]
+
+			emphasis := &inline{kind: kind}
+			for node := openernode.next; node != nil && node != closernode; {
+				next := node.next
+				emphasis.appendchild(node)
+				node = next
+			}
+			openernode.insertafter(emphasis)
+
+			// Delimiters between the opener and the closer can not match anymore
+			opener.next = closer
+			closer.prev = opener
+
+			if opener.count == 0 {
+				openernode.unlink()
+				ip.removedelimiter(opener)
+			}
+			if closer.count == 0 {
+				closernode.unlink()
+				next := closer.next
+				ip.removedelimiter(closer)
+				closer = next
+			}
+		} else {
+			closer = closer.next
+			openersbottom[key] = oldcloser.prev
+			if !oldcloser.canopen {
+				ip.removedelimiter(oldcloser)
+			}
+		}
+	}
+
+	for ip.delimiters != nil && ip.delimiters != bottom {
+		ip.removedelimiter(ip.delimiters)
+	}
+}
+
+func (ip *inlineparser) addbracket(container *inline, index int, image bool) {
+	node := text("[")
+	if image {
+		node.literal = "!["
+	}
+	container.appendchild(node)
+	if ip.brackets != nil {
+		ip.brackets.bracketafter = true
+	}
+	ip.brackets = &bracket{node: node, prev: ip.brackets, previousdelimiter: ip.delimiters, index: index, image: image, active: true}
+}
+
+func (ip *inlineparser) bang(container *inline) bool {
+	if peek(ip.subject, ip.pos+1) == '[' {
+		ip.addbracket(container, ip.pos+1, true)
+		ip.pos += 2
+		return true
+	}
+	container.appendchild(text("!"))
+	ip.pos++
+	return true
+}
+
+func (ip *inlineparser) spnl() {
+	ip.pos += len(respnl.FindString(ip.subject[ip.pos:]))
+}
+
+// linklabel returns the length of the link label at the current position, or 0.
+func (ip *inlineparser) linklabel() int {
+	return len(relinklabel.FindString(ip.subject[ip.pos:]))
+}
+
+func (ip *inlineparser) linkdestination() (string, bool) {
+	if match := relinkdest.FindString(ip.subject[ip.pos:]); len(match) > 0 {
+		ip.pos += len(match)
+		return normalizeurl(unescape(match[1 : len(match)-1])), true
+	}
+	if ip.peek() == '<' {
+		return "", false
+	}
+
+	start := ip.pos
+	parens := 0
+	for ip.pos < len(ip.subject) {
+		c := ip.subject[ip.pos]
+		if c == '\\' && ip.pos+1 < len(ip.subject) && strings.IndexByte(escapable, ip.subject[ip.pos+1]) >= 0 {
+			ip.pos += 2
+		} else if c == '(' {
+			ip.pos++
+			parens++
+		} else if c == ')' {
+			if parens < 1 {
+				break
+			}
+			ip.pos++
+			parens--
+		} else if c <= ' ' {
+			break
+		} else {
+			ip.pos++
+		}
+	}
+	if (ip.pos == start && ip.peek() != ')') || parens != 0 {
+		ip.pos = start
+		return "", false
+	}
+	return normalizeurl(unescape(ip.subject[start:ip.pos])), true
+}
+
+func (ip *inlineparser) linktitle() (string, bool) {
+	match := relinktitle.FindString(ip.subject[ip.pos:])
+	if len(match) == 0 {
+		return "", false
+	}
+	ip.pos += len(match)
+	return unescape(match[1 : len(match)-1]), true
+}
+
+func (ip *inlineparser) closebracket(container *inline) bool {
+	ip.pos++
+	start := ip.pos
+
+	opener := ip.brackets
+	if opener == nil {
+		container.appendchild(text("]"))
+		return true
+	}
+	if !opener.active {
+		container.appendchild(text("]"))
+		ip.brackets = opener.prev
+		return true
+	}
+
+	// Footnote references, e.g. [^1]
+	if !opener.image && peek(ip.subject, opener.index+1) == '^' {
+		label := normalizelabel(ip.subject[opener.index+2 : start-1])
+		if _, ok := ip.footnotes[label]; ok && !strings.ContainsAny(label, " \t\n") {
+			for ip.delimiters != opener.previousdelimiter {
+				ip.removedelimiter(ip.delimiters)
+			}
+			for node := opener.node.next; node != nil; {
+				next := node.next
+				node.unlink()
+				node = next
+			}
+			opener.node.kind = kindfootnoteref
+			opener.node.literal = ""
+			opener.node.label = label
+			ip.brackets = opener.prev
+			return true
+		}
+	}
+
+	matched := false
+	var dest, title string
+	if ip.peek() == '(' {
+		ip.pos++
+		ip.spnl()
+		var ok bool
+		if dest, ok = ip.linkdestination(); ok {
+			ip.spnl()
+			if unicode.IsSpace(rune(ip.subject[ip.pos-1])) {
+				title, _ = ip.linktitle()
+			}
+			ip.spnl()
+			if ip.peek() == ')' {
+				ip.pos++
+				matched = true
+			}
+		}
+		if !matched {
+			ip.pos = start
+			title = ""
+		}
+	}
+	if !matched {
+		// Full, collapsed and shortcut reference links
+		before := ip.pos
+		n := ip.linklabel()
+		label := ""
+		if n > 2 {
+			label = ip.subject[before : before+n]
+		} else if !opener.bracketafter {
+			label = ip.subject[opener.index:start]
+		}
+		if n == 0 {
+			ip.pos = start
+		} else {
+			ip.pos = before + n
+		}
+		if len(label) > 0 {
+			if ref, ok := ip.refs[normalizelabel(label[1:len(label)-1])]; ok {
+				dest, title = ref.dest, ref.title
+				matched = true
+			}
+		}
+		if !matched {
+			ip.pos = start
+		}
+	}
+
+	if !matched {
+		ip.brackets = opener.prev
+		container.appendchild(text("]"))
+		return true
+	}
+
+	node := &inline{kind: kindlink, dest: dest, title: title}
+	if opener.image {
+		node.kind = kindimage
+	}
+	for child := opener.node.next; child != nil; {
+		next := child.next
+		node.appendchild(child)
+		child = next
+	}
+	container.appendchild(node)
+	ip.processemphasis(opener.previousdelimiter)
+	ip.brackets = opener.prev
+	opener.node.unlink()
+
+	// Links can not contain other links
+	if !opener.image {
+		for b := ip.brackets; b != nil; b = b.prev {
+			if !b.image {
+				b.active = false
+			}
+		}
+	}
+	return true
+}
+
+func (ip *inlineparser) autolink(container *inline) bool {
+	if match := reemailautolink.FindStringSubmatch(ip.subject[ip.pos:]); match != nil {
+		ip.pos += len(match[0])
+		link := &inline{kind: kindlink, dest: normalizeurl("mailto:" + match[1])}
+		link.appendchild(text(match[1]))
+		container.appendchild(link)
+		return true
+	}
+	if match := reautolink.FindString(ip.subject[ip.pos:]); len(match) > 0 {
+		ip.pos += len(match)
+		url := match[1 : len(match)-1]
+		link := &inline{kind: kindlink, dest: normalizeurl(url)}
+		link.appendchild(text(url))
+		container.appendchild(link)
+		return true
+	}
+	return false
+}
+
+func (ip *inlineparser) htmltag(container *inline) bool {
+	match := rehtmltag.FindString(ip.subject[ip.pos:])
+	if len(match) == 0 {
+		return false
+	}
+	ip.pos += len(match)
+	container.appendchild(&inline{kind: kindhtml, literal: match})
+	return true
+}
+
+func (ip *inlineparser) entity(container *inline) bool {
+	match := reentity.FindString(ip.subject[ip.pos:])
+	if len(match) == 0 {
+		return false
+	}
+	ip.pos += len(match)
+	container.appendchild(text(html.UnescapeString(match)))
+	return true
+}
+
+// reference parses a link reference definition at the start of the subject and
+// returns its length, or 0 if there is none.
+func (ip *inlineparser) reference() int {
+	n := ip.linklabel()
+	if n == 0 {
+		return 0
+	}
+	label := ip.subject[1 : n-1]
+	ip.pos = n
+	if ip.peek() != ':' {
+		return 0
+	}
+	ip.pos++
+	ip.spnl()
+	dest, ok := ip.linkdestination()
+	if !ok {
+		return 0
+	}
+
+	beforetitle := ip.pos
+	ip.spnl()
+	title := ""
+	if ip.pos != beforetitle {
+		title, ok = ip.linktitle()
+		if !ok {
+			ip.pos = beforetitle
+		}
+	}
+	if !relineend.MatchString(ip.subject[ip.pos:]) {
+		if len(title) == 0 {
+			return 0
+		}
+		// The title must be followed by the end of the line, the definition may end before it
+		title = ""
+		ip.pos = beforetitle
+		if !relineend.MatchString(ip.subject[ip.pos:]) {
+			return 0
+		}
+	}
+	ip.pos += len(relineend.FindString(ip.subject[ip.pos:]))
+
+	normalized := normalizelabel(label)
+	if len(normalized) == 0 {
+		return 0
+	}
+	if _, exists := ip.refs[normalized]; !exists {
+		ip.refs[normalized] = reference{dest: dest, title: title}
+	}
+	return ip.pos
+}
+
+// mergetext merges adjacent text nodes, the delimiters that did not match are left as separate nodes.
+func mergetext(n *inline) {
+	for child := n.first; child != nil; child = child.next {
+		if child.kind == kindtext {
+			for child.next != nil && child.next.kind == kindtext {
+				child.literal += child.next.literal
+				child.next.unlink()
+			}
+		}
+		mergetext(child)
+	}
+}
+
+// autolink turns the urls in the text nodes outside of links into links, see
+// https://github.github.com/gfm/#autolinks-extension-
+func autolink(n *inline) {
+	for child := n.first; child != nil; child = child.next {
+		switch child.kind {
+		case kindlink, kindimage, kindcode, kindhtml:
+			continue
+		case kindtext:
+			child = linkify(child)
+		default:
+			autolink(child)
+		}
+	}
+}
+
+// linkify splits the text node around the urls it contains and returns the last node.
+func linkify(node *inline) *inline {
+	s := node.literal
+	locs := reextendedlink.FindAllStringIndex(s, -1)
+	if locs == nil {
+		return node
+	}
+
+	last := node
+	pos := 0
+	for _, loc := range locs {
+		start, end := loc[0], loc[1]
+		if start > 0 && strings.IndexByte(" \t\n*_~(", s[start-1]) < 0 {
+			continue
+		}
+		end = start + trimurl(s[start:end])
+		url := s[start:end]
+		if strings.HasPrefix(url, "www.") && !strings.Contains(url[4:], ".") || len(strings.TrimRight(url, "/")) <= len("https://") && !strings.HasPrefix(url, "www.") {
+			continue
+		}
+
+		if start > pos {
+			t := text(s[pos:start])
+			last.insertafter(t)
+			last = t
+		}
+		dest := url
+		if strings.HasPrefix(url, "www.") {
+			dest = "http://" + url
+		}
+		link := &inline{kind: kindlink, dest: normalizeurl(dest)}
+		link.appendchild(text(url))
+		last.insertafter(link)
+		last = link
+		pos = end
+	}
+	if pos == 0 {
+		return node
+	}
+	if pos < len(s) {
+		t := text(s[pos:])
+		last.insertafter(t)
+		last = t
+	}
+	node.unlink()
+	return last
+}
+
+// trimurl returns the length of the url without its trailing punctuation and unbalanced parentheses.
+func trimurl(url string) int {
+	for len(url) > 0 {
+		c := url[len(url)-1]
+		switch {
+		case strings.IndexByte("?!.,:*_~'\"", c) >= 0:
+			url = url[:len(url)-1]
+		case c == ')' && strings.Count(url, ")") > strings.Count(url, "("):
+			url = url[:len(url)-1]
+		default:
+			return len(url)
+		}
+	}
+	return 0
+}
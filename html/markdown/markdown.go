@@ -0,0 +1,481 @@
+// Package markdown converts CommonMark documents into html.Node trees, so that docs and
+// blog posts are rendered with the same components as the rest of the app. The GitHub
+// Flavored Markdown extensions for tables, task lists, strikethrough, autolinks and
+// footnotes are supported, and headings get ids to link to them.
+//
+// Example usage:
+//
+//	md := markdown.New(&markdown.Options{
+//		Components: markdown.Components{
+//			"table": table.New,
+//			"thead": table.Header,
+//			"tbody": table.Body,
+//			"tr":    table.Row,
+//			"th":    table.Head,
+//			"td":    table.Cell,
+//			"a":     Link,
+//		},
+//		CodeBlock: func(block markdown.CodeBlock) html.Node {
+//			return CodeSnippet(block.Language, block.Code)
+//		},
+//	})
+//
+//	doc := md.Parse(source)
+//	html.Article(
+//		html.Aside(TableOfContents(doc.TOC())),
+//		doc,
+//	)
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// Component creates the element of a Markdown construct from its attributes and children,
+// e.g. a component of the ui registry.
+type Component func(items ...html.Item) html.Node
+
+// Components maps tag names (e.g. "table", "a", "h2") to the components that render them
+// in place of the plain HTML elements.
+type Components map[string]Component
+
+// CodeBlock is a fenced or indented code block of a document.
+type CodeBlock struct {
+	// First word of the info string, e.g. "go" for a block fenced with ```go
+	Language string
+	// Full info string of fenced code blocks
+	Info string
+	Code string
+}
+
+type Options struct {
+	Components Components
+	// CodeBlock renders the code blocks, they are rendered as <pre><code> elements if it is nil
+	CodeBlock func(CodeBlock) html.Node
+	// Whether the raw HTML of the document is rendered, it is omitted by default.
+	// Only enable it for trusted documents.
+	Unsafe bool
+}
+
+// Markdown parses documents with a set of options.
+type Markdown struct {
+	options *Options
+}
+
+// New creates a Markdown parser with the given options, nil options use the defaults.
+func New(options *Options) *Markdown {
+	if options == nil {
+		options = &Options{}
+	}
+	return &Markdown{options: options}
+}
+
+// Parse parses the source with the default options, see Markdown.Parse.
+func Parse(source string) *Document {
+	return New(nil).Parse(source)
+}
+
+// Parse parses the given Markdown source into a document.
+func (m *Markdown) Parse(source string) *Document {
+	p := newparser()
+	root := p.parse(source)
+	doc := &Document{root: root, options: m.options, footnotes: p.footnotes}
+	ip := &inlineparser{refs: p.refs, footnotes: p.footnotes}
+	ids := map[string]int{}
+
+	var walk func(b *block)
+	walk = func(b *block) {
+		switch b.kind {
+		case kindparagraph:
+			b.inlines = ip.parse(string(b.content))
+		case kindheading:
+			b.inlines = ip.parse(string(b.content))
+			b.id = uniqueid(ids, slug(b.inlines.text()))
+			doc.headings = append(doc.headings, Heading{Level: b.level, ID: b.id, Text: b.inlines.text()})
+		case kindtable:
+			for _, line := range strings.Split(strings.TrimSuffix(string(b.content), "\n"), "\n") {
+				if len(strings.TrimSpace(line)) == 0 {
+					// The delimiter row is consumed and leaves an empty line behind
+					continue
+				}
+				row := []*inline{}
+				for i, cell := range splitrow(line) {
+					if i == len(b.aligns) {
+						break
+					}
+					row = append(row, ip.parse(strings.ReplaceAll(cell, `\|`, "|")))
+				}
+				b.rows = append(b.rows, row)
+			}
+		}
+		b.content = nil
+		for _, child := range b.children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return doc
+}
+
+// Heading is an entry of the table of contents of a document.
+type Heading struct {
+	// Level of the heading, 1 for <h1>
+	Level int
+	// Id of the heading element, generated from its text
+	ID   string
+	Text string
+	// Headings of the section of the heading
+	Children []Heading
+}
+
+/*
+Document is a parsed Markdown document, it implements the html.Node interface and
+renders into the tree of elements of its content.
+
+Headings get ids that are generated from their text like GitHub does, e.g. "Getting
+Started" becomes "getting-started", and duplicates get numeric suffixes.
+*/
+type Document struct {
+	root      *block
+	options   *Options
+	footnotes map[string]*block
+	headings  []Heading
+}
+
+// Implements the Item interface.
+func (*Document) Item() {}
+
+// Implements the Node interface.
+func (*Document) Release() {}
+
+// Implements the Node interface.
+func (d *Document) Render(w html.ChunkWriter) error {
+	// The node is not released, hook and component nodes of the options keep rendering it
+	// in their chunks after Render returns
+	return d.Node().Render(w)
+}
+
+// Node builds the tree of the document, the caller owns the returned node.
+func (d *Document) Node() html.Node {
+	r := &renderer{options: d.options, doc: d, numbers: map[string]int{}, refs: map[string]int{}}
+	content := r.children(d.root, false)
+	if footnotes := r.footnotes(); footnotes != nil {
+		content = append(content, footnotes)
+	}
+	return fragment(content)
+}
+
+// TOC returns the headings of the document nested by their levels.
+func (d *Document) TOC() []Heading {
+	var nest func(headings []Heading) []Heading
+	nest = func(headings []Heading) []Heading {
+		toc := []Heading{}
+		for i := 0; i < len(headings); {
+			heading := headings[i]
+			j := i + 1
+			for j < len(headings) && headings[j].Level > heading.Level {
+				j++
+			}
+			heading.Children = nest(headings[i+1 : j])
+			toc = append(toc, heading)
+			i = j
+		}
+		return toc
+	}
+	return nest(d.headings)
+}
+
+// slug creates the id of a heading from its text.
+func slug(text string) string {
+	b := new(strings.Builder)
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+func uniqueid(ids map[string]int, id string) string {
+	n, ok := ids[id]
+	ids[id] = n + 1
+	if !ok {
+		return id
+	}
+	for {
+		candidate := id + "-" + strconv.Itoa(n)
+		if _, exists := ids[candidate]; !exists {
+			ids[candidate] = 1
+			return candidate
+		}
+		n++
+	}
+}
+
+type renderer struct {
+	options *Options
+	doc     *Document
+	// Numbers of the referenced footnotes by their labels, in the order of their first reference
+	numbers map[string]int
+	order   []string
+	// Number of references to each footnote
+	refs map[string]int
+}
+
+func (r *renderer) el(tag string, items ...html.Item) html.Node {
+	if component, ok := r.options.Components[tag]; ok {
+		return component(items...)
+	}
+	return html.El(tag, items...)
+}
+
+func (r *renderer) children(b *block, tight bool) []html.Item {
+	items := []html.Item{}
+	for _, child := range b.children {
+		if node := r.block(child, tight); node != nil {
+			items = append(items, node)
+		}
+	}
+	return items
+}
+
+func (r *renderer) block(b *block, tight bool) html.Node {
+	switch b.kind {
+	case kindparagraph:
+		if tight {
+			return fragment(r.inlines(b.inlines))
+		}
+		return r.el("p", r.inlines(b.inlines)...)
+	case kindheading:
+		return r.el("h"+strconv.Itoa(b.level), append([]html.Item{html.ID(b.id)}, r.inlines(b.inlines)...)...)
+	case kindthematicbreak:
+		return r.el("hr")
+	case kindblockquote:
+		return r.el("blockquote", r.children(b, false)...)
+	case kindlist:
+		tag := "ul"
+		items := []html.Item{}
+		if b.list.ordered {
+			tag = "ol"
+			if b.list.start != 1 {
+				items = append(items, html.Attr("start", strconv.Itoa(b.list.start)))
+			}
+		}
+		for _, item := range b.children {
+			if item.task > 0 {
+				items = append(items, html.Class("contains-task-list"))
+				break
+			}
+		}
+		for _, item := range b.children {
+			items = append(items, r.item(item, b.list.tight))
+		}
+		return r.el(tag, items...)
+	case kindcodeblock:
+		block := CodeBlock{Info: b.info, Code: b.literal}
+		block.Language, _, _ = strings.Cut(b.info, " ")
+		if r.options.CodeBlock != nil {
+			return r.options.CodeBlock(block)
+		}
+		code := []html.Item{html.Text(block.Code)}
+		if len(block.Language) > 0 {
+			code = append([]html.Item{html.Class("language-" + block.Language)}, code...)
+		}
+		return r.el("pre", r.el("code", code...))
+	case kindhtmlblock:
+		if r.options.Unsafe {
+			return html.RawUnsafe(b.literal)
+		}
+		return nil
+	case kindtable:
+		return r.table(b)
+	default:
+		// Footnote definitions are rendered at the end of the document
+		return nil
+	}
+}
+
+func (r *renderer) item(b *block, tight bool) html.Node {
+	items := []html.Item{}
+	if b.task == 0 {
+		return r.el("li", append(items, r.children(b, tight)...)...)
+	}
+
+	checkbox := []html.Item{html.Type("checkbox"), html.Attr("disabled", "")}
+	if b.task == 2 {
+		checkbox = append(checkbox, html.Attr("checked", ""))
+	}
+	items = append(items, html.Class("task-list-item"))
+	for i, child := range b.children {
+		node := r.block(child, tight)
+		if i == 0 {
+			// The checkbox is the first child of the paragraph of the item
+			content := append([]html.Item{r.el("input", checkbox...), html.Text(" ")}, r.inlines(child.inlines)...)
+			if tight {
+				node = fragment(content)
+			} else {
+				node = r.el("p", content...)
+			}
+		}
+		if node != nil {
+			items = append(items, node)
+		}
+	}
+	return r.el("li", items...)
+}
+
+func (r *renderer) table(b *block) html.Node {
+	row := func(cells []*inline, tag string) html.Node {
+		items := []html.Item{}
+		for i, align := range b.aligns {
+			cell := []html.Item{}
+			if len(align) > 0 {
+				cell = append(cell, html.Align(align))
+			}
+			if i < len(cells) {
+				cell = append(cell, r.inlines(cells[i])...)
+			}
+			items = append(items, r.el(tag, cell...))
+		}
+		return r.el("tr", items...)
+	}
+
+	items := []html.Item{r.el("thead", row(b.rows[0], "th"))}
+	if len(b.rows) > 1 {
+		rows := []html.Item{}
+		for _, cells := range b.rows[1:] {
+			rows = append(rows, row(cells, "td"))
+		}
+		items = append(items, r.el("tbody", rows...))
+	}
+	return r.el("table", items...)
+}
+
+func (r *renderer) inlines(n *inline) []html.Item {
+	items := []html.Item{}
+	for child := n.first; child != nil; child = child.next {
+		if node := r.inline(child); node != nil {
+			items = append(items, node)
+		}
+	}
+	return items
+}
+
+func (r *renderer) inline(n *inline) html.Node {
+	switch n.kind {
+	case kindtext:
+		if len(n.literal) == 0 {
+			return nil
+		}
+		return html.Text(n.literal)
+	case kindsoftbreak:
+		return html.Text("\n")
+	case kindlinebreak:
+		return html.Fragment(r.el("br"), html.Text("\n"))
+	case kindcode:
+		return r.el("code", html.Text(n.literal))
+	case kindemphasis:
+		return r.el("em", r.inlines(n)...)
+	case kindstrong:
+		return r.el("strong", r.inlines(n)...)
+	case kindstrikethrough:
+		return r.el("del", r.inlines(n)...)
+	case kindlink:
+		items := []html.Item{html.Href(n.dest)}
+		if len(n.title) > 0 {
+			items = append(items, html.TitleAttr(n.title))
+		}
+		return r.el("a", append(items, r.inlines(n)...)...)
+	case kindimage:
+		items := []html.Item{html.Src(n.dest), html.Alt(n.text())}
+		if len(n.title) > 0 {
+			items = append(items, html.TitleAttr(n.title))
+		}
+		return r.el("img", items...)
+	case kindhtml:
+		if r.options.Unsafe {
+			return html.RawUnsafe(n.literal)
+		}
+		return nil
+	case kindfootnoteref:
+		number, ok := r.numbers[n.label]
+		if !ok {
+			number = len(r.order) + 1
+			r.numbers[n.label] = number
+			r.order = append(r.order, n.label)
+		}
+		r.refs[n.label]++
+		id := "fnref-" + strconv.Itoa(number)
+		if count := r.refs[n.label]; count > 1 {
+			id += "-" + strconv.Itoa(count)
+		}
+		return r.el("sup", r.el("a",
+			html.Href("#fn-"+strconv.Itoa(number)),
+			html.ID(id),
+			html.Data("footnote-ref", ""),
+			html.Text(strconv.Itoa(number)),
+		))
+	default:
+		return nil
+	}
+}
+
+// footnotes renders the definitions of the referenced footnotes in the order of their
+// first reference, with links back to the references.
+func (r *renderer) footnotes() html.Node {
+	if len(r.order) == 0 {
+		return nil
+	}
+	items := []html.Item{}
+	// Footnotes may reference other footnotes, which are appended to the order while rendering
+	for i := 0; i < len(r.order); i++ {
+		label := r.order[i]
+		number := strconv.Itoa(i + 1)
+		definition := r.doc.footnotes[label]
+
+		backrefs := []html.Item{}
+		for j := 1; j <= r.refs[label]; j++ {
+			href := "#fnref-" + number
+			if j > 1 {
+				href += "-" + strconv.Itoa(j)
+			}
+			backrefs = append(backrefs, html.Text(" "), r.el("a",
+				html.Href(href),
+				html.Data("footnote-backref", ""),
+				html.Aria("label", "Back to reference "+number),
+				html.Text("↩"),
+			))
+		}
+		children := []html.Item{}
+		for j, child := range definition.children {
+			if j == len(definition.children)-1 && child.kind == kindparagraph {
+				// The back references are placed at the end of the last paragraph
+				children = append(children, r.el("p", append(r.inlines(child.inlines), backrefs...)...))
+				backrefs = nil
+			} else if node := r.block(child, false); node != nil {
+				children = append(children, node)
+			}
+		}
+		if backrefs != nil {
+			children = append(children, fragment(backrefs))
+		}
+		items = append(items, r.el("li", append([]html.Item{html.ID("fn-" + number)}, children...)...))
+	}
+	return r.el("section", html.Class("footnotes"), html.Data("footnotes", ""), r.el("ol", items...))
+}
+
+// fragment groups the rendered nodes, they are rendered without a wrapping element.
+func fragment(items []html.Item) html.Node {
+	nodes := make([]html.Node, 0, len(items))
+	for _, item := range items {
+		nodes = append(nodes, item.(html.Node))
+	}
+	return html.Fragment(nodes...)
+}
@@ -0,0 +1,95 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/htmltest"
+	"github.com/canpacis/pacis/html/markdown"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, doc *markdown.Document) string {
+	return htmltest.Render(t, doc).String()
+}
+
+func TestMarkdown(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		Source   string
+		Expected string
+	}{
+		{"# Hello *world*", `<h1 id="hello-world">Hello <em>world</em></h1>`},
+		{"Title\n=====", `<h1 id="title">Title</h1>`},
+		{"**bold** _em_ `code` ~~del~~", `<p><strong>bold</strong> <em>em</em> <code>code</code> <del>del</del></p>`},
+		{"*foo**bar**baz* **foo*", `<p><em>foo<strong>bar</strong>baz</em> *<em>foo</em></p>`},
+		{"line  \nbreak\\\nend", "<p>line<br>\nbreak<br>\nend</p>"},
+		{"&copy; &nope; \\*", `<p>© &amp;nope; *</p>`},
+		{"- a\n- b\n\n3. x\n4. y", `<ul><li>a</li><li>b</li></ul><ol start="3"><li>x</li><li>y</li></ol>`},
+		{"- a\n\n- b\n  - c", `<ul><li><p>a</p></li><li><p>b</p><ul><li>c</li></ul></li></ul>`},
+		{"> quote\nlazy\n\n***", "<blockquote><p>quote\nlazy</p></blockquote><hr>"},
+		{"```go\nfunc main() {\n\tprintln()\n}\n```", "<pre><code class=\"language-go\">func main() {\n\tprintln()\n}\n</code></pre>"},
+		{"    indented", "<pre><code>indented\n</code></pre>"},
+		{"[a](/a \"t\") ![b](/b.png) <https://c.com>\n\n[ref]\n\n[ref]: /r", `<p><a href="/a" title="t">a</a> <img src="/b.png" alt="b"> <a href="https://c.com">https://c.com</a></p><p><a href="/r">ref</a></p>`},
+		{"[x](javascript:alert(1))", `<p><a href="about:invalid#ZpacisZ">x</a></p>`},
+		{"visit www.example.com/docs.", `<p>visit <a href="http://www.example.com/docs">www.example.com/docs</a>.</p>`},
+		{"<div>raw</div>\n\nx <b>y</b>", `<p>x y</p>`},
+		{"- [ ] todo\n- [x] done", `<ul class="contains-task-list"><li class="task-list-item"><input type="checkbox" disabled> todo</li><li class="task-list-item"><input type="checkbox" disabled checked> done</li></ul>`},
+		{"| a | b |\n|:--|--:|\n| 1 | 2 \\| 3 |", `<table><thead><tr><th align="left">a</th><th align="right">b</th></tr></thead><tbody><tr><td align="left">1</td><td align="right">2 | 3</td></tr></tbody></table>`},
+		{
+			"a[^1] b[^1]\n\n[^1]: note",
+			`<p>a<sup><a href="#fn-1" id="fnref-1" data-footnote-ref>1</a></sup> b<sup><a href="#fn-1" id="fnref-1-2" data-footnote-ref>1</a></sup></p>` +
+				`<section class="footnotes" data-footnotes><ol><li id="fn-1"><p>note ` +
+				`<a href="#fnref-1" data-footnote-backref aria-label="Back to reference 1">↩</a> ` +
+				`<a href="#fnref-1-2" data-footnote-backref aria-label="Back to reference 1">↩</a></p></li></ol></section>`,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(test.Expected, render(t, markdown.Parse(test.Source)), test.Source)
+	}
+
+	unsafe := markdown.New(&markdown.Options{Unsafe: true})
+	assert.Equal("<div>raw</div><p>x <b>y</b></p>", render(t, unsafe.Parse("<div>raw</div>\n\nx <b>y</b>")))
+}
+
+func TestComponents(t *testing.T) {
+	assert := assert.New(t)
+
+	md := markdown.New(&markdown.Options{
+		Components: markdown.Components{
+			"a": func(items ...html.Item) html.Node {
+				return html.A(append(items, html.Class("link"))...)
+			},
+			"table": func(items ...html.Item) html.Node {
+				return html.Div(html.Class("table"), html.Table(items...))
+			},
+		},
+		CodeBlock: func(block markdown.CodeBlock) html.Node {
+			return html.Pre(html.Data("language", block.Language), html.Text(block.Code))
+		},
+	})
+
+	doc := htmltest.Render(t, md.Parse("[docs](/docs)\n\n| a |\n| - |\n| 1 |\n\n```sh title=install\nmake\n```"))
+	assert.True(doc.AttrEquals("a", "class", "link"))
+	assert.True(doc.HasElement("div.table > table > tbody > tr > td"))
+	assert.True(doc.AttrEquals("pre", "data-language", "sh"))
+	assert.True(doc.TextContains("pre", "make"))
+}
+
+func TestTOC(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := markdown.Parse("# Intro\n## Install\n### From *source*\n## Install\n# API")
+	assert.Equal([]markdown.Heading{
+		{Level: 1, ID: "intro", Text: "Intro", Children: []markdown.Heading{
+			{Level: 2, ID: "install", Text: "Install", Children: []markdown.Heading{
+				{Level: 3, ID: "from-source", Text: "From source", Children: []markdown.Heading{}},
+			}},
+			{Level: 2, ID: "install-1", Text: "Install", Children: []markdown.Heading{}},
+		}},
+		{Level: 1, ID: "api", Text: "API", Children: []markdown.Heading{}},
+	}, doc.TOC())
+	assert.Contains(render(t, doc), `<h2 id="install-1">Install</h2>`)
+}
@@ -0,0 +1,342 @@
+// Package mathml provides constructors for MathML elements and attributes that can be
+// used together with the html package. MathML elements keep the case of their tag and
+// attribute names and self close when they have no children.
+//
+// Example usage:
+//
+//	mathml.Math(
+//		mathml.Display("block"),
+//
+//		mathml.Mfrac(
+//			mathml.Mi(html.Text("a")),
+//			mathml.Mn(html.Text("2")),
+//		),
+//	)
+package mathml
+
+import "github.com/canpacis/pacis/html"
+
+// Namespace is the XML namespace of MathML elements, used as the value of the xmlns attribute.
+const Namespace = "http://www.w3.org/1998/Math/MathML"
+
+// El creates a new MathML element with the given tag name, see html.El.
+func El(name string, items ...html.Item) *html.Element {
+	return html.NSEl(html.NamespaceMathML, name, items...)
+}
+
+// Elements
+
+/*
+# The Annotation element
+
+The <annotation> MathML element contains an annotation to the MathML expression in a textual format, for example LaTeX.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/annotation
+*/
+func Annotation(items ...html.Item) *html.Element { return El("annotation", items...) }
+
+/*
+# The XML Annotation element
+
+The <annotation-xml> MathML element contains an annotation to the MathML expression in an XML format, for example Content MathML or SVG.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/annotation-xml
+*/
+func AnnotationXML(items ...html.Item) *html.Element { return El("annotation-xml", items...) }
+
+/*
+# The Top-level math element
+
+The <math> MathML element is the top-level MathML element, used to write a single mathematical formula. It can be placed in HTML content where flow content is permitted.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/math
+*/
+func Math(items ...html.Item) *html.Element { return El("math", items...) }
+
+/*
+# The Error message element
+
+The <merror> MathML element is used to display contents as error messages.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/merror
+*/
+func Merror(items ...html.Item) *html.Element { return El("merror", items...) }
+
+/*
+# The Fraction element
+
+The <mfrac> MathML element is used to display fractions. It can also be used to mark up fraction-like objects such as binomial coefficients and Legendre symbols.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mfrac
+*/
+func Mfrac(items ...html.Item) *html.Element { return El("mfrac", items...) }
+
+/*
+# The Identifier element
+
+The <mi> MathML element indicates that the content should be rendered as an identifier such as function names, variables or symbolic constants.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mi
+*/
+func Mi(items ...html.Item) *html.Element { return El("mi", items...) }
+
+/*
+# The Multiscripts element
+
+The <mmultiscripts> MathML element is used to attach an arbitrary number of subscripts and superscripts to an expression at once, generalizing the <msubsup> element.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mmultiscripts
+*/
+func Mmultiscripts(items ...html.Item) *html.Element { return El("mmultiscripts", items...) }
+
+/*
+# The Number element
+
+The <mn> MathML element represents a numeric literal which is normally a sequence of digits with a possible separator (a dot or a comma).
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mn
+*/
+func Mn(items ...html.Item) *html.Element { return El("mn", items...) }
+
+/*
+# The Operator element
+
+The <mo> MathML element represents an operator in a broad sense. Besides operators in strict mathematical meaning, this element also includes "operators" like parentheses, separators like comma and semicolon, or "absolute value" bars.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mo
+*/
+func Mo(items ...html.Item) *html.Element { return El("mo", items...) }
+
+/*
+# The Overscript element
+
+The <mover> MathML element is used to attach an accent or a limit over an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mover
+*/
+func Mover(items ...html.Item) *html.Element { return El("mover", items...) }
+
+/*
+# The Padded element
+
+The <mpadded> MathML element is used to add extra padding and to set the general adjustment of position and size of enclosed contents.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mpadded
+*/
+func Mpadded(items ...html.Item) *html.Element { return El("mpadded", items...) }
+
+/*
+# The Phantom element
+
+The <mphantom> MathML element is rendered invisibly, but dimensions (such as height, width, and baseline position) are still kept.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mphantom
+*/
+func Mphantom(items ...html.Item) *html.Element { return El("mphantom", items...) }
+
+/*
+# The Prescripts element
+
+The <mprescripts> MathML element separates the postscripts from the prescripts of an <mmultiscripts> element.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mprescripts
+*/
+func Mprescripts(items ...html.Item) *html.Element { return El("mprescripts", items...) }
+
+/*
+# The Radical element
+
+The <mroot> MathML element is used to display roots with an explicit index.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mroot
+*/
+func Mroot(items ...html.Item) *html.Element { return El("mroot", items...) }
+
+/*
+# The Row element
+
+The <mrow> MathML element is used to group sub-expressions, which usually contain one or more operators with their respective operands.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mrow
+*/
+func Mrow(items ...html.Item) *html.Element { return El("mrow", items...) }
+
+/*
+# The String literal element
+
+The <ms> MathML element represents a string literal meant to be interpreted by programming languages and computer algebra systems.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/ms
+*/
+func Ms(items ...html.Item) *html.Element { return El("ms", items...) }
+
+/*
+# The Space element
+
+The <mspace> MathML element is used to display a blank space, whose size is set by its attributes.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mspace
+*/
+func Mspace(items ...html.Item) *html.Element { return El("mspace", items...) }
+
+/*
+# The Square root element
+
+The <msqrt> MathML element is used to display square roots (no index is displayed).
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/msqrt
+*/
+func Msqrt(items ...html.Item) *html.Element { return El("msqrt", items...) }
+
+/*
+# The Style change element
+
+The <mstyle> MathML element is used to change the style of its children.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mstyle
+*/
+func Mstyle(items ...html.Item) *html.Element { return El("mstyle", items...) }
+
+/*
+# The Subscript element
+
+The <msub> MathML element is used to attach a subscript to an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/msub
+*/
+func Msub(items ...html.Item) *html.Element { return El("msub", items...) }
+
+/*
+# The Subscript-superscript element
+
+The <msubsup> MathML element is used to attach both a subscript and a superscript, together, to an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/msubsup
+*/
+func Msubsup(items ...html.Item) *html.Element { return El("msubsup", items...) }
+
+/*
+# The Superscript element
+
+The <msup> MathML element is used to attach a superscript to an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/msup
+*/
+func Msup(items ...html.Item) *html.Element { return El("msup", items...) }
+
+/*
+# The Table element
+
+The <mtable> MathML element allows you to create tables or matrices.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mtable
+*/
+func Mtable(items ...html.Item) *html.Element { return El("mtable", items...) }
+
+/*
+# The Table cell element
+
+The <mtd> MathML element represents a cell in a table or a matrix. It may only appear in a <mtr> element.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mtd
+*/
+func Mtd(items ...html.Item) *html.Element { return El("mtd", items...) }
+
+/*
+# The Text element
+
+The <mtext> MathML element is used to render arbitrary text with no notational meaning, such as comments or annotations.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mtext
+*/
+func Mtext(items ...html.Item) *html.Element { return El("mtext", items...) }
+
+/*
+# The Table row element
+
+The <mtr> MathML element represents a row in a table or a matrix. It may only appear in a <mtable> element.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/mtr
+*/
+func Mtr(items ...html.Item) *html.Element { return El("mtr", items...) }
+
+/*
+# The Underscript element
+
+The <munder> MathML element is used to attach an accent or a limit under an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/munder
+*/
+func Munder(items ...html.Item) *html.Element { return El("munder", items...) }
+
+/*
+# The Underscript-overscript element
+
+The <munderover> MathML element is used to attach accents or limits both under and over an expression.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/munderover
+*/
+func Munderover(items ...html.Item) *html.Element { return El("munderover", items...) }
+
+/*
+# The Semantics element
+
+The <semantics> MathML element associates annotations with a MathML expression, for example its text source as a lightweight markup language or mathematical meaning expressed in a special XML dialect.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/semantics
+*/
+func Semantics(items ...html.Item) *html.Element { return El("semantics", items...) }
+
+// Attributes
+
+/*
+# MathML attribute: display
+
+The display attribute specifies how the enclosed MathML markup should be rendered, either "block" or "inline".
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Element/math#display
+*/
+func Display(value string) *html.Attribute { return html.Attr("display", value) }
+
+/*
+# MathML attribute: mathvariant
+
+The mathvariant attribute, in conjunction with a single character token element, specifies a logical class for that character, e.g. "normal".
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Global_attributes/mathvariant
+*/
+func MathVariant(value string) *html.Attribute { return html.Attr("mathvariant", value) }
+
+/*
+# MathML attribute: displaystyle
+
+The displaystyle attribute sets the math-style of a MathML element, "true" for normal and "false" for compact.
+
+https://developer.mozilla.org/en-US/docs/Web/MathML/Reference/Global_attributes/displaystyle
+*/
+func DisplayStyle(value bool) *html.Attribute { return html.Attr("displaystyle", boolean(value)) }
+
+// Sets the xmlns attribute to the MathML namespace, required for standalone MathML documents.
+func XMLNS() *html.Attribute { return html.Attr("xmlns", Namespace) }
+
+func boolean(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
+
+func Stretchy(value bool) *html.Attribute        { return html.Attr("stretchy", boolean(value)) }
+func Fence(value bool) *html.Attribute           { return html.Attr("fence", boolean(value)) }
+func Separator(value bool) *html.Attribute       { return html.Attr("separator", boolean(value)) }
+func Accent(value bool) *html.Attribute          { return html.Attr("accent", boolean(value)) }
+func AccentUnder(value bool) *html.Attribute     { return html.Attr("accentunder", boolean(value)) }
+func Form(value string) *html.Attribute          { return html.Attr("form", value) }
+func LineThickness(value string) *html.Attribute { return html.Attr("linethickness", value) }
+func Encoding(value string) *html.Attribute      { return html.Attr("encoding", value) }
+func ScriptLevel(value string) *html.Attribute   { return html.Attr("scriptlevel", value) }
+func Lspace(value string) *html.Attribute        { return html.Attr("lspace", value) }
+func Rspace(value string) *html.Attribute        { return html.Attr("rspace", value) }
+func Width(value string) *html.Attribute         { return html.Attr("width", value) }
+func Height(value string) *html.Attribute        { return html.Attr("height", value) }
+func Depth(value string) *html.Attribute         { return html.Attr("depth", value) }
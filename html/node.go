@@ -12,7 +12,7 @@
 //   - Frag: Represents a group of child nodes rendered in sequence.
 //   - Element: Represents an HTML element with tag name, attributes, and children.
 //   - Property: Interface for properties that can be applied to elements (e.g., Attribute).
-//   - Attribute: Represents an HTML attribute key-value pair.
+//   - Attribute: Represents an HTML attribute key-value pair, escaped according to its context.
 //   - El: Constructs a new Element with the given tag name, children, and properties.
 //   - VoidEl: Constructs a new void (self-closing) Element.
 //   - Fragment: Helper to create a Frag from a variadic list of nodes.
@@ -36,6 +36,7 @@ import (
 	"html"
 	"io"
 	"iter"
+	"maps"
 	"slices"
 	"strings"
 	"sync"
@@ -48,6 +49,21 @@ type Item interface {
 
 // Node represents an element that can be rendered to an io.Writer within a given context.
 // Implementations of Node should define the Render method to output their content.
+//
+// Release hands the node and its descendants back to the pools they were taken from, so
+// that the next elements reuse them. The ownership rules are:
+//
+//   - The code that builds a tree owns it and is the only one that may release it, once,
+//     after every chunk of the tree is rendered (including the dynamic ones). A released
+//     node must not be used anymore.
+//   - Nodes that are shared, e.g. kept in package level variables, cached or referenced by
+//     more than one tree, must be frozen. Frozen elements and their descendants are skipped
+//     by Release, so html.Doctype and the other shared nodes of this package stay intact,
+//     see Element.Freeze.
+//   - Nothing in this module releases the trees it did not build: the server keeps the
+//     trees of its pages for its whole lifetime and the nodes returned by components and
+//     hooks are left to the garbage collector, since they may be shared. Trees that are
+//     not released are simply collected.
 type Node interface {
 	Item
 	Render(ChunkWriter) error
@@ -62,7 +78,28 @@ func (Text) Item() {}
 
 // Implements the Node interface.
 func (t Text) Render(w ChunkWriter) error {
-	w.Write(StaticChunk(html.EscapeString(string(t))))
+	f := formatof(w)
+	switch {
+	case f.mode == ModeXML:
+		writestatic(w, escapexml(string(t), false))
+	case f.mode == ModeCompact || f.preserve > 0:
+		writestatic(w, html.EscapeString(string(t)))
+	case f.mode == ModeMinify:
+		writestatic(w, html.EscapeString(collapse(string(t))))
+	case f.inline:
+		writestatic(w, html.EscapeString(string(t)))
+	default:
+		// Pretty mode, text nodes between elements are written on their own line
+		text := strings.TrimSpace(string(t))
+		if len(text) == 0 {
+			return nil
+		}
+		if f.started {
+			writestatic(w, f.newline())
+		}
+		f.started = true
+		writestatic(w, html.EscapeString(text))
+	}
 	return nil
 }
 
@@ -99,6 +136,12 @@ func (f Frag) Render(w ChunkWriter) error {
 	return nil
 }
 
+// Freeze freezes the nodes of the fragment and returns it back, see Element.Freeze.
+func (f Frag) Freeze() Frag {
+	freeze(f)
+	return f
+}
+
 // Fragment creates a Frag from the provided nodes, allowing multiple nodes to be grouped together.
 // It accepts a variadic number of Node arguments and returns a Frag containing them.
 func Fragment(nodes ...Node) Frag {
@@ -121,9 +164,11 @@ type Property interface {
 }
 
 // Attribute represents a key-value pair used as an attribute in an HTML node.
+// Attribute values are escaped according to their context when rendered, see EscapeAttr.
 type Attribute struct {
 	Key   string
 	Value string
+	raw   bool
 }
 
 // Implements the Item interface.
@@ -138,13 +183,14 @@ func (a *Attribute) Apply(el *Element) {
 	if a.Key == "class" {
 		el.AddClass(a.Value)
 	} else {
-		el.SetAttribute(a.Key, a.Value)
+		el.setattr(a.Key, a.Value, a.raw)
 	}
 }
 
 type DeferredAttribute struct {
 	key string
 	fn  func(context.Context) string
+	raw bool
 }
 
 // Implements the Item interface.
@@ -156,24 +202,41 @@ func (*DeferredAttribute) LifeCycle() PropertyLifeCycle {
 
 // Implements the Propterty interface.
 func (a *DeferredAttribute) Apply(ctx context.Context, w io.Writer) error {
-	var rhs = ""
-	value := a.fn(ctx)
-	if len(value) > 0 {
-		rhs = "=" + "\"" + value + "\""
+	return a.chunk(ModeCompact)(ctx, w)
+}
+
+// chunk returns the chunk that writes the attribute formatted with the render mode of the
+// element, e.g. XML escapes the value and writes empty values instead of omitting them.
+func (a *DeferredAttribute) chunk(mode RenderMode) DynamicChunk {
+	return func(ctx context.Context, w io.Writer) error {
+		_, err := w.Write(appendattr(nil, a.key, a.fn(ctx), a.raw, mode))
+		return err
 	}
-	_, err := fmt.Fprintf(w, " %s%s", a.key, rhs)
-	return err
 }
 
+// Creates a new DeferredAttribute whose value is computed with the render context.
+// The computed value is escaped like any other attribute value.
 func DeferredAttr(key string, fn func(context.Context) string) *DeferredAttribute {
 	return &DeferredAttribute{key: key, fn: fn}
 }
 
+// Creates a new DeferredAttribute whose computed value is written as is, without escaping.
+// Only use it with values that are known to be safe.
+func DeferredAttrUnsafe(key string, fn func(context.Context) string) *DeferredAttribute {
+	return &DeferredAttribute{key: key, fn: fn, raw: true}
+}
+
 // Creates a new Attribute with given key and value.
 func Attr(key string, value string) *Attribute {
 	return &Attribute{Key: key, Value: value}
 }
 
+// Creates a new Attribute with given key and value that is written as is, without escaping.
+// It is the attribute counterpart of RawUnsafe, only use it with values that are known to be safe.
+func AttrUnsafe(key string, value string) *Attribute {
+	return &Attribute{Key: key, Value: value, raw: true}
+}
+
 type Component func(context.Context) Node
 
 // Implements the Item interface.
@@ -186,38 +249,70 @@ func (Component) Release() {
 
 // Implements the Node interface.
 func (c Component) Render(cw ChunkWriter) error {
+	// The formatting state at the position of the component, every render starts from a copy of it
+	snapshot := *formatof(cw)
+
 	cw.Write(DynamicChunk(func(ctx context.Context, w io.Writer) error {
-		writer := &teecw{ChunkWriter: cw, fn: func(c Chunk) error {
-			if err := Render(c, ctx, w); err != nil {
-				return err
-			}
-			return nil
-		}}
-		c(ctx).Render(writer)
-		return nil
+		writer := newteecw(snapshot, ctx, w)
+		defer writer.release()
+		if err := c(ctx).Render(writer); err != nil {
+			return err
+		}
+		return writer.flush()
 	}))
 	return nil
 }
 
+// Namespace is the namespace an element belongs to. It decides how the tag name
+// of the element is written and whether the element can self close.
+type Namespace int
+
+const (
+	NamespaceHTML = Namespace(iota)
+	NamespaceSVG
+	NamespaceMathML
+)
+
 // Element represents an HTML element node, containing the element's name,
-// a map of its attributes, a slice of child nodes, and a flag indicating
-// whether the element is a void (self-closing) element.
+// a map of its attributes, a slice of child nodes, and the namespace the
+// element belongs to.
 type Element struct {
 	nodes         []Node
 	properties    []Property
-	attributelist []*Attribute
+	attributelist []Attribute
 	name          string
+	ns            Namespace
 	meta          map[string]any
+	// Frozen elements are never mutated nor pooled, see Freeze
+	frozen bool
 }
 
+// Tag returns the tag name of the element. Tag names of HTML elements are lower-cased,
+// tag names of SVG and MathML elements keep their case (e.g. linearGradient).
 func (e *Element) Tag() string {
-	if strings.HasPrefix(e.name, "!") {
+	if e.ns != NamespaceHTML || strings.HasPrefix(e.name, "!") {
 		return e.name
 	}
 	return strings.ToLower(e.name)
 }
 
+// Namespace returns the namespace the element belongs to.
+func (e *Element) Namespace() Namespace {
+	return e.ns
+}
+
+// mutate panics if the element is frozen, it is called by every method that modifies the element.
+func (e *Element) mutate() {
+	if e.frozen {
+		panic(fmt.Sprintf("Frozen <%s> element cannot be modified, use the element returned by Mutable instead", e.Tag()))
+	}
+}
+
 func (e *Element) Set(key string, value any) {
+	e.mutate()
+	if e.meta == nil {
+		e.meta = make(map[string]any)
+	}
 	e.meta[key] = value
 }
 
@@ -226,16 +321,23 @@ func (e *Element) Get(key string) any {
 }
 
 func (e *Element) SetAttribute(key, value string) {
+	e.setattr(key, value, false)
+}
+
+func (e *Element) setattr(key, value string, raw bool) {
+	e.mutate()
 	for i, attr := range e.attributelist {
 		if attr.Key == key {
 			e.attributelist[i].Value = value
+			e.attributelist[i].raw = raw
 			return
 		}
 	}
-	e.attributelist = append(e.attributelist, &Attribute{Key: key, Value: value})
+	e.attributelist = append(e.attributelist, Attribute{Key: key, Value: value, raw: raw})
 }
 
 func (e *Element) AddClass(class string) {
+	e.mutate()
 	attr := e.GetAttribute("class")
 	if len(attr) == 0 {
 		attr = class
@@ -254,6 +356,17 @@ func (e *Element) GetAttribute(key string) string {
 	return ""
 }
 
+// HasAttribute reports whether the element has the attribute, boolean attributes are set
+// with an empty value.
+func (e *Element) HasAttribute(key string) bool {
+	for _, attr := range e.attributelist {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *Element) GetAttributes() map[string]string {
 	attrs := map[string]string{}
 	for _, attr := range e.attributelist {
@@ -262,10 +375,27 @@ func (e *Element) GetAttributes() map[string]string {
 	return attrs
 }
 
+// Attributes returns an iterator over the element's attributes in the order they were set.
+func (e *Element) Attributes() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, attr := range e.attributelist {
+			if !yield(attr.Key, attr.Value) {
+				return
+			}
+		}
+	}
+}
+
 func (e *Element) SetAttributes(list map[string]string) {
-	e.attributelist = []*Attribute{}
+	e.mutate()
+	previous := e.attributelist
+	e.attributelist = []Attribute{}
 	for key, value := range list {
-		e.attributelist = append(e.attributelist, &Attribute{Key: key, Value: value})
+		// Keep trusted values trusted as long as they are unchanged
+		raw := slices.ContainsFunc(previous, func(attr Attribute) bool {
+			return attr.raw && attr.Key == key && attr.Value == value
+		})
+		e.attributelist = append(e.attributelist, Attribute{Key: key, Value: value, raw: raw})
 	}
 }
 
@@ -274,6 +404,7 @@ func (e *Element) GetNodes() []Node {
 }
 
 func (e *Element) AppendNode(node Node) {
+	e.mutate()
 	e.nodes = append(e.nodes, node)
 }
 
@@ -281,18 +412,63 @@ func (e *Element) AppendNode(node Node) {
 func (*Element) Item() {}
 
 // Implements the Node interface.
+//
+// Releases the children of the element and puts the element back to the pool,
+// it is reused by the next call to El. Frozen elements are never released.
 func (e *Element) Release() {
+	if e.frozen {
+		return
+	}
+	for _, node := range e.nodes {
+		node.Release()
+	}
+	// Clear the slices before truncating them, so that the pool does not keep the old items alive
+	clear(e.nodes)
+	e.nodes = e.nodes[:0]
+	clear(e.properties)
+	e.properties = e.properties[:0]
+	clear(e.attributelist)
+	e.attributelist = e.attributelist[:0]
+	clear(e.meta)
+	e.name = ""
+	e.ns = NamespaceHTML
 	elpool.Put(e)
 }
 
-var voidelements = []string{"!DOCTYPE", "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+var voidelements = map[string]bool{
+	"!DOCTYPE": true, "area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
 
 // Implements the Node interface.
 func (e *Element) Render(w ChunkWriter) error {
-	w.Write(StaticChunk(fmt.Appendf(nil, "<%s", e.Tag())))
+	f := formatof(w)
+	if f.mode == ModeXML {
+		return e.renderxml(w)
+	}
+	omitclose := f.omitclose
+	f.omitclose = false
+
+	if f.mode == ModePretty && f.preserve == 0 && !f.inline && f.started {
+		writestatic(w, f.newline())
+	}
+	f.started = true
+
+	tag := e.Tag()
+	writestatic(w, "<", tag)
+
+	attrmode := f.mode
+	if e.ns != NamespaceHTML {
+		// An unquoted value would swallow the slash of a self closing tag
+		attrmode = ModeCompact
+	}
 
 	if len(e.properties) > 0 {
 		for _, prop := range e.properties {
+			if attr, ok := prop.(*DeferredAttribute); ok {
+				w.Write(attr.chunk(attrmode))
+				continue
+			}
 			applier, ok := prop.(interface {
 				Apply(context.Context, io.Writer) error
 			})
@@ -303,41 +479,172 @@ func (e *Element) Render(w ChunkWriter) error {
 			}
 		}
 	}
+	for i := range e.attributelist {
+		writeattr(w, &e.attributelist[i], attrmode)
+	}
+	if e.needsnonce(f, tag) {
+		w.Write(noncechunk)
+	}
 
-	for _, attr := range e.attributelist {
-		var rhs string
-		if len(attr.Value) != 0 {
-			rhs = "=" + "\"" + attr.Value + "\""
-		}
-		w.Write(StaticChunk(fmt.Appendf(nil, " %s%s", attr.Key, rhs)))
+	if e.ns != NamespaceHTML && len(e.nodes) == 0 {
+		// Foreign elements without children self close
+		writestatic(w, "/>")
+		return nil
 	}
 
-	w.Write(StaticChunk(">"))
+	writestatic(w, ">")
 
-	if slices.Contains(voidelements, e.Tag()) {
+	if e.ns == NamespaceHTML && voidelements[tag] {
 		return nil
 	}
 
-	for _, node := range e.nodes {
+	if f.mode == ModeCompact {
+		for _, node := range e.nodes {
+			if err := node.Render(w); err != nil {
+				return err
+			}
+		}
+		e.writeoutlet(w, tag)
+		writestatic(w, "</", tag, ">")
+		return nil
+	}
+
+	return e.renderformatted(w, f, tag, omitclose)
+}
+
+// writeoutlet marks the end of <head> and <body> elements, where the nodes hoisted with Once are placed.
+func (e *Element) writeoutlet(w ChunkWriter, tag string) {
+	if e.ns != NamespaceHTML {
+		return
+	}
+	switch tag {
+	case "head":
+		w.Write(headoutlet)
+	case "body":
+		w.Write(bodyoutlet)
+	}
+}
+
+// renderformatted renders the children and the closing tag of the element in pretty or minify mode.
+func (e *Element) renderformatted(w ChunkWriter, f *format, tag string, omitclose bool) error {
+	preserve := preserves(tag)
+	inline := f.inline
+	if preserve {
+		f.preserve++
+	}
+	if f.mode == ModePretty {
+		f.inline = inline || f.preserve > 0 || textonly(e.nodes)
+	}
+	f.depth++
+
+	nodes := flatten(e.nodes)
+	for i, node := range nodes {
+		if child, ok := node.(*Element); ok && f.mode == ModeMinify {
+			var next Node
+			if i+1 < len(nodes) {
+				next = nodes[i+1]
+			}
+			f.omitclose = omittable(child, next, tag)
+		}
 		if err := node.Render(w); err != nil {
 			return err
 		}
+		f.omitclose = false
+	}
+	e.writeoutlet(w, tag)
+
+	f.depth--
+	if preserve {
+		f.preserve--
+	}
+	if f.mode == ModePretty && !f.inline && f.preserve == 0 {
+		writestatic(w, f.newline())
 	}
+	f.inline = inline
 
-	w.Write(StaticChunk(fmt.Appendf(nil, "</%s>", e.Tag())))
+	if !omitclose {
+		writestatic(w, "</", tag, ">")
+	}
 	return nil
 }
 
-func (e *Element) Clone() *Element {
-	attributelist := make([]*Attribute, len(e.attributelist))
-	copy(attributelist, e.attributelist)
+/*
+Freeze marks the element and its descendants as immutable and returns the element back.
+Frozen elements can be shared between trees and rendered from many goroutines at once:
+they are never put back to the pool by Release and the methods that modify an element
+panic when they are called on a frozen element. Use Mutable to get a modifiable copy.
 
+Nodes that are kept in package level variables should be frozen before they are used.
+Properties are applied by copying their values into the element, so shared properties
+(e.g. x.Cloak) do not need to be frozen.
+
+Usage:
+
+	var Logo = html.Img(html.Src("/logo.svg"), html.Alt("Pacis")).Freeze()
+*/
+func (e *Element) Freeze() *Element {
+	freeze(e)
+	return e
+}
+
+// Frozen reports whether the element is frozen, see Freeze.
+func (e *Element) Frozen() bool {
+	return e.frozen
+}
+
+// MergeAttributes sets the attributes of from on the element in their order, overriding
+// the attributes with the same keys. Values set with AttrUnsafe stay unescaped and class
+// lists are merged with the classes of from first.
+func (e *Element) MergeAttributes(from *Element) {
+	e.mutate()
+	class := e.GetAttribute("class")
+	for _, attr := range from.attributelist {
+		if attr.Key == "class" && len(class) > 0 {
+			attr.Value = attr.Value + " " + class
+		}
+		e.setattr(attr.Key, attr.Value, attr.raw)
+	}
+}
+
+// Mutable returns the element if it is not frozen, otherwise an unfrozen copy of it (copy-on-write).
+// The copy shares the children of the frozen element, which stay frozen.
+func (e *Element) Mutable() *Element {
+	if !e.frozen {
+		return e
+	}
+	return e.Clone()
+}
+
+func freeze(node Node) {
+	switch node := node.(type) {
+	case *Element:
+		if node.frozen {
+			return
+		}
+		node.frozen = true
+		for _, child := range node.nodes {
+			freeze(child)
+		}
+	case Frag:
+		for _, child := range node {
+			freeze(child)
+		}
+	case *ErrorBoundaryNode:
+		freeze(node.node)
+	case interface{ freeze() }:
+		node.freeze()
+	}
+}
+
+// Clone returns an unfrozen shallow copy of the element, the children are shared with the original element.
+func (e *Element) Clone() *Element {
 	return &Element{
-		nodes:         e.nodes,
-		properties:    e.properties,
-		attributelist: attributelist,
+		nodes:         slices.Clone(e.nodes),
+		properties:    slices.Clone(e.properties),
+		attributelist: slices.Clone(e.attributelist),
 		name:          e.name,
-		meta:          e.meta,
+		ns:            e.ns,
+		meta:          maps.Clone(e.meta),
 	}
 }
 
@@ -347,14 +654,17 @@ var propspool = sync.Pool{
 	},
 }
 
+func putprops(props *[]Property) {
+	clear(*props)
+	*props = (*props)[:0]
+	propspool.Put(props)
+}
+
+// The slices and the meta map of new elements are allocated lazily, so that the trees
+// that are not released do not cost more than unpooled elements.
 var elpool = sync.Pool{
 	New: func() any {
-		return &Element{
-			nodes:         []Node{},
-			properties:    []Property{},
-			attributelist: []*Attribute{},
-			meta:          make(map[string]any),
-		}
+		return new(Element)
 	},
 }
 
@@ -363,13 +673,20 @@ var elpool = sync.Pool{
 // while Properties are collected and applied to the element after all children are processed.
 // Returns a pointer to the constructed Element.
 func El(name string, items ...Item) *Element {
-	el := elpool.New().(*Element)
+	return NSEl(NamespaceHTML, name, items...)
+}
+
+// NSEl creates a new Element in the given namespace, see El. Elements in the SVG and
+// MathML namespaces keep the case of their tag name and self close when they have no children.
+func NSEl(ns Namespace, name string, items ...Item) *Element {
+	el := elpool.Get().(*Element)
 	el.name = name
+	el.ns = ns
 
-	immediate := propspool.New().(*[]Property)
-	defer propspool.Put(immediate)
-	static := propspool.New().(*[]Property)
-	defer propspool.Put(static)
+	immediate := propspool.Get().(*[]Property)
+	defer putprops(immediate)
+	static := propspool.Get().(*[]Property)
+	defer putprops(static)
 
 	for _, item := range items {
 		switch item := item.(type) {
@@ -620,7 +937,7 @@ func (n *JSONNode) Render(w ChunkWriter) error {
 	enc := json.NewEncoder(buf)
 	enc.SetIndent("", n.Indent)
 	if err := enc.Encode(n.Data); err != nil {
-		panic(err)
+		return fmt.Errorf("failed to encode json node: %w", err)
 	}
 	w.Write(StaticChunk(buf.Bytes()))
 	return nil
@@ -643,6 +960,6 @@ func (RawUnsafe) Release() {
 
 // Implements the Node interface.
 func (t RawUnsafe) Render(w ChunkWriter) error {
-	w.Write(StaticChunk(string(t)))
+	writestatic(w, string(t))
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/canpacis/pacis/components"
 	"github.com/canpacis/pacis/html"
 	"github.com/stretchr/testify/assert"
 )
@@ -63,6 +64,12 @@ func TestNodeChunks(t *testing.T) {
 			Node:     html.Div(html.P(html.Text("Hello, World!"))),
 			Rendered: "<div><p>Hello, World!</p></div>",
 		},
+		{
+			// Adjacent static chunks are coalesced
+			Node:     html.Div(html.ID("app"), html.P(html.Text("Hello")), html.Br()),
+			Rendered: `<div id="app"><p>Hello</p><br></div>`,
+			N:        1,
+		},
 		{
 			Node:     html.Fragment(html.Head(), html.Body()),
 			Rendered: "<head></head><body></body>",
@@ -92,3 +99,106 @@ func TestNodeChunks(t *testing.T) {
 		test.Assert(assert)
 	}
 }
+
+func TestAttributeEscaping(t *testing.T) {
+	tests := []ChunkTest{
+		{
+			Node:     html.Div(html.TitleAttr(`"><script>alert(1)</script>`)),
+			Rendered: `<div title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"></div>`,
+		},
+		{
+			Node:     html.A(html.Href("/docs?a=1&b=2")),
+			Rendered: `<a href="/docs?a=1&amp;b=2"></a>`,
+		},
+		{
+			Node:     html.A(html.Href(" JavaScript:alert(1)")),
+			Rendered: `<a href="about:invalid#ZpacisZ"></a>`,
+		},
+		{
+			Node:     html.A(html.Href("java\tscript:alert(1)")),
+			Rendered: `<a href="about:invalid#ZpacisZ"></a>`,
+		},
+		{
+			Node:     html.A(html.Href("mailto:hi@example.com")),
+			Rendered: `<a href="mailto:hi@example.com"></a>`,
+		},
+		{
+			Node:     html.Img(html.Attr("srcset", "/a.png 1x, javascript:alert(1) 2x")),
+			Rendered: `<img srcset="about:invalid#ZpacisZ">`,
+		},
+		{
+			Node:     html.Button(html.Attr("@click", `open = !open && name != "x"`)),
+			Rendered: `<button @click="open = !open &amp;&amp; name != &#34;x&#34;"></button>`,
+		},
+		{
+			Node:     html.Div(html.Attr("x-show", "count > 0 && 'a' < 'b'")),
+			Rendered: `<div x-show="count > 0 &amp;&amp; 'a' < 'b'"></div>`,
+		},
+		{
+			Node:     html.Div(html.StyleAttr("width: expression(alert(1))")),
+			Rendered: `<div style="ZpacisZ"></div>`,
+		},
+		{
+			Node:     html.Div(html.StyleAttr("color: red; font-family: 'Inter'")),
+			Rendered: `<div style="color: red; font-family: 'Inter'"></div>`,
+		},
+		{
+			Node:     html.A(html.AttrUnsafe("href", "javascript:void(0)")),
+			Rendered: `<a href="javascript:void(0)"></a>`,
+		},
+		{
+			Node:     html.Body(html.DeferredAttr("class", func(ctx context.Context) string { return `"dark` })),
+			Rendered: `<body class="&#34;dark"></body>`,
+		},
+		{
+			Node:     html.Body(html.DeferredAttrUnsafe("class", func(ctx context.Context) string { return `<dark>` })),
+			Rendered: `<body class="<dark>"></body>`,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, test := range tests {
+		test.Assert(assert)
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	assert := assert.New(t)
+
+	el := html.A(html.Class("link"), html.Href("/"), html.AttrUnsafe("x-on:click", "open = !open && count > 0"))
+	el.MergeAttributes(html.Button(html.Class("btn"), html.Href("/home"), html.AttrUnsafe("x-data", "{ open: false }")))
+	assert.Equal(`<a class="btn link" href="/home" x-on:click="open = !open && count > 0" x-data="{ open: false }"></a>`, render(el))
+
+	// Trusted attributes of the element stay trusted when it adopts its child
+	button := html.Button(html.AttrUnsafe("x-on:click", "a && b"), components.AsChild, html.A(html.Href("/")))
+	assert.Equal(`<a href="/" x-on:click="a && b"></a>`, render(button))
+}
+
+func TestHasAttribute(t *testing.T) {
+	assert := assert.New(t)
+
+	el := html.Input(html.Attr("disabled", ""), html.Class("field"))
+	assert.True(el.HasAttribute("disabled"))
+	assert.True(el.HasAttribute("class"))
+	assert.False(el.HasAttribute("id"))
+}
+
+func TestRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	for range 3 {
+		// Released elements are reused by the next elements, none of their state may leak
+		el := html.Div(html.ID("first"), html.Class("a"), html.P(html.Text("first")))
+		assert.Equal(`<div id="first" class="a"><p>first</p></div>`, render(el))
+		el.Release()
+
+		el = html.Span(html.Text("second"))
+		assert.Equal(`<span>second</span>`, render(el))
+		el.Release()
+	}
+
+	original := html.Div(html.ID("original"), html.Text("text"))
+	clone := original.Clone()
+	original.Release()
+	assert.Equal(`<div id="original">text</div>`, render(clone))
+}
@@ -0,0 +1,111 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+type noncekey struct{}
+
+// WithNonce returns a copy of ctx that carries the Content-Security-Policy nonce of a request.
+// Every <script> and <style> element written to a chunk writer with nonces enabled and
+// rendered with the returned context gets a nonce attribute with the given value, unless
+// the element sets one explicitly, see EnableNonce.
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, noncekey{}, nonce)
+}
+
+// EnableNonce makes the <script> and <style> elements that are written to w get the nonce
+// of the render context, see WithNonce. Each of them is split into a dynamic chunk for the
+// nonce, so it is off by default and pages without a Content-Security-Policy keep their
+// static output in a single chunk. The server enables it for the pages that use the CSP
+// middleware. Writers that are not implemented by this package are left unchanged.
+func EnableNonce(w ChunkWriter) {
+	if f, ok := w.(formatted); ok {
+		f.format().nonce = true
+	}
+}
+
+// Nonce returns the Content-Security-Policy nonce of ctx or an empty string, see WithNonce.
+func Nonce(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	nonce, _ := ctx.Value(noncekey{}).(string)
+	return nonce
+}
+
+// NonceAttr creates a property that sets the nonce attribute of an element to the nonce
+// of the render context, see WithNonce. <script> and <style> elements have it by default.
+func NonceAttr() Property {
+	return nonceproperty
+}
+
+var (
+	nonceproperty = &nonceattr{}
+	noncechunk    = DynamicChunk(nonceproperty.Apply)
+)
+
+// nonceattr writes the nonce attribute of the render context, if there is one.
+type nonceattr struct{}
+
+// Implements the Item interface.
+func (*nonceattr) Item() {}
+
+func (*nonceattr) LifeCycle() PropertyLifeCycle {
+	return LifeCycleDeferred
+}
+
+// Implements the Property interface.
+func (*nonceattr) Apply(ctx context.Context, w io.Writer) error {
+	nonce := Nonce(ctx)
+	if len(nonce) == 0 {
+		return nil
+	}
+	_, err := w.Write(appendattr(nil, "nonce", nonce, false, ModeCompact))
+	return err
+}
+
+// needsnonce reports whether the element is a <script> or a <style> element without a nonce
+// that is written with nonces enabled.
+func (e *Element) needsnonce(f *format, tag string) bool {
+	if !f.nonce || e.ns != NamespaceHTML || (tag != "script" && tag != "style") {
+		return false
+	}
+	for _, prop := range e.properties {
+		if prop == Property(nonceproperty) {
+			return false
+		}
+	}
+	for _, attr := range e.attributelist {
+		if attr.Key == "nonce" {
+			return false
+		}
+	}
+	return true
+}
+
+// Cached output is shared between requests, so it is rendered with a placeholder nonce
+// that is replaced with the nonce of the request it is written to.
+const placeholdernonce = "pacis-cached-nonce"
+
+var placeholderattr = appendattr(nil, "nonce", placeholdernonce, false, ModeCompact)
+
+// withplaceholdernonce returns a context to render output that is shared between requests with.
+func withplaceholdernonce(ctx context.Context) context.Context {
+	return WithNonce(ctx, placeholdernonce)
+}
+
+// resolvenonce replaces the placeholder nonce in output rendered with withplaceholdernonce
+// by the nonce of ctx, or removes the attribute if ctx has none.
+func resolvenonce(ctx context.Context, rendered []byte) []byte {
+	if !bytes.Contains(rendered, placeholderattr) {
+		return rendered
+	}
+	var attr []byte
+	if nonce := Nonce(ctx); len(nonce) > 0 {
+		attr = appendattr(nil, "nonce", nonce, false, ModeCompact)
+	}
+	return bytes.ReplaceAll(rendered, placeholderattr, attr)
+}
@@ -0,0 +1,114 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/x"
+	"github.com/stretchr/testify/assert"
+)
+
+// renderNonce renders the node with nonces enabled, see html.EnableNonce.
+func renderNonce(ctx context.Context, node html.Node) (string, error) {
+	cw := html.NewChunkWriter()
+	html.EnableNonce(cw)
+	if err := node.Render(cw); err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, ctx, buf); err != nil {
+			return buf.String(), err
+		}
+	}
+	return buf.String(), nil
+}
+
+func TestNonce(t *testing.T) {
+	assert := assert.New(t)
+
+	node := html.Div(
+		x.Data(map[string]int{"count": 1}),
+		html.Script(html.RawUnsafe("init()")),
+		html.Style(html.RawUnsafe("div{}")),
+		html.Script(html.Attr("nonce", "explicit")),
+		html.Link(html.NonceAttr()),
+		html.Cached(func(context.Context) string { return "nonce" }, 0, html.Component(func(context.Context) html.Node {
+			return html.Script(html.Src("/app.js"))
+		})).WithCache(html.NewCache(1)),
+	)
+
+	rendered, err := renderNonce(html.WithNonce(context.Background(), "abc"), node)
+	assert.NoError(err)
+	assert.Contains(rendered, `<script nonce="abc">init()</script><style nonce="abc">div{}</style><script nonce="explicit"></script><link nonce="abc">`)
+	assert.Contains(rendered, `<script src="/app.js" nonce="abc"></script>`)
+	assert.Regexp(`<script type="application/json" id="[^"]+" nonce="abc">`, rendered)
+
+	// Cached output gets the nonce of the request it is rendered in
+	rendered, err = renderNonce(html.WithNonce(context.Background(), "def"), node)
+	assert.NoError(err)
+	assert.Contains(rendered, `<script src="/app.js" nonce="def"></script>`)
+	assert.NotContains(rendered, "abc")
+
+	rendered, err = renderNonce(context.Background(), node)
+	assert.NoError(err)
+	assert.Contains(rendered, `<script>init()</script><style>div{}</style><script nonce="explicit"></script><link>`)
+	assert.Contains(rendered, `<script src="/app.js"></script>`)
+}
+
+func TestCachedNonce(t *testing.T) {
+	assert := assert.New(t)
+
+	node := html.Html(
+		html.Head(),
+		html.Body(
+			html.Cached(func(context.Context) string { return "widget" }, 0, html.Component(func(context.Context) html.Node {
+				return html.Fragment(
+					html.Once("widget-style", html.Style(html.RawUnsafe(".widget{}"))).Hoist(html.HoistHead),
+					html.HeadPortal("widget-script", html.Script(html.Src("/widget.js"))),
+					html.Div(html.Class("widget")),
+				)
+			})).WithCache(html.NewCache(1)),
+		),
+	)
+
+	// Hoisted and head portal nodes of cached output get the nonce of the request they are rendered in
+	for _, nonce := range []string{"abc", "def"} {
+		ctx := html.WithResponseScope(html.WithNonce(context.Background(), nonce))
+		rendered, err := renderNonce(ctx, node)
+		assert.NoError(err)
+		assert.Equal(
+			`<html><head><script src="/widget.js" nonce="`+nonce+`"></script><style nonce="`+nonce+`">.widget{}</style></head><body><div class="widget"></div></body></html>`,
+			string(html.ResolveHoisted(ctx, []byte(rendered))),
+		)
+	}
+
+	ctx := html.WithResponseScope(context.Background())
+	rendered, err := renderNonce(ctx, node)
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><script src="/widget.js"></script><style>.widget{}</style></head><body><div class="widget"></div></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+}
+
+func TestNonceDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without nonces the static output of a page stays in a single chunk
+	node := html.Main(html.Style(html.RawUnsafe("div{}")), html.Script(html.RawUnsafe("init()")))
+	cw := html.NewChunkWriter()
+	assert.NoError(node.Render(cw))
+	assert.Len(cw.Chunks(), 1)
+
+	rendered, err := renderContext(html.WithNonce(context.Background(), "abc"), node)
+	assert.NoError(err)
+	assert.Equal(`<main><style>div{}</style><script>init()</script></main>`, rendered)
+
+	// Explicit nonce attributes are written anyway
+	rendered, err = renderContext(html.WithNonce(context.Background(), "abc"), html.Meta(html.NonceAttr()))
+	assert.NoError(err)
+	assert.Equal(`<meta nonce="abc">`, rendered)
+}
@@ -0,0 +1,387 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"sync"
+)
+
+// HoistTarget is the place a node rendered with Once is moved to, see OnceNode.Hoist.
+type HoistTarget int
+
+const (
+	// Renders the node where it appears in the tree.
+	HoistNone = HoistTarget(iota)
+	// Renders the node at the end of the <head> element.
+	HoistHead
+	// Renders the node at the end of the <body> element.
+	HoistBody
+)
+
+// Outlets are written to the output before the closing tags of <head> and <body> elements
+// and replaced with the hoisted nodes once the response is rendered, see ResolveHoisted.
+// They end with the random token of the response, so that page content that happens to
+// contain an outlet is never replaced, see scope.outlet.
+var outlets = [...][]byte{
+	HoistHead: []byte("<!--pacis:hoist:head:"),
+	HoistBody: []byte("<!--pacis:hoist:body:"),
+}
+
+// The outlets in cached output have a placeholder token that is replaced with the token
+// of the response the output is written to, see resolveoutlets.
+const cachedtoken = "cached"
+
+// scope holds the state of a single response.
+type scope struct {
+	mu sync.Mutex
+	// Random token of the outlets of the response
+	token string
+	seen  map[string]bool
+	// Rendered nodes per hoist target
+	hoisted [len(outlets)]bytes.Buffer
+	// Rendered head portal nodes in the order of their first registration, see HeadPortal
+	portal     [][]byte
+	portalkeys map[string]int
+	// Whether the outlets are already replaced, nodes that are hoisted afterwards (e.g. in async chunks) are rendered in place
+	resolved bool
+	// Effects registered in the scope, only kept for the output of cached nodes, see record
+	effects []effect
+	record  bool
+	// Once keys the parent response rendered before the cached node, see recordscope
+	inherited map[string]bool
+}
+
+// effect is a once key or a head portal node that a render registered in its response
+// scope along with the output it hoisted. Cached nodes replay them in the responses they
+// are written to, since their component does not run on cache hits, see CachedNode.
+type effect struct {
+	portal bool
+	key    string
+	target HoistTarget
+	value  []byte
+}
+
+// recordscope returns a copy of ctx with a response scope that keeps the effects registered
+// in it. The scope starts with the once keys the response scope of ctx has seen, so that
+// they are not rendered again in place. Hoisted nodes with these keys are still recorded
+// for the other responses the output is written to.
+func recordscope(ctx context.Context) (context.Context, *scope) {
+	s := &scope{token: cachedtoken, seen: map[string]bool{}, portalkeys: map[string]int{}, record: true, inherited: map[string]bool{}}
+	if parent := scopeof(ctx); parent != nil {
+		parent.mu.Lock()
+		for key := range parent.seen {
+			s.seen[key] = true
+			s.inherited[key] = true
+		}
+		parent.mu.Unlock()
+	}
+	return context.WithValue(ctx, scopekey{}, s), s
+}
+
+// once registers the first render of a once key, hoisting its value unless the target is
+// HoistNone. The lock must be held by the caller.
+func (s *scope) once(key string, target HoistTarget, value []byte) {
+	s.seen[key] = true
+	if target != HoistNone {
+		s.hoisted[target].Write(value)
+	}
+	if s.record {
+		s.effects = append(s.effects, effect{key: key, target: target, value: value})
+	}
+}
+
+// setportal registers a head portal node, replacing the one registered with the same key.
+// The lock must be held by the caller.
+func (s *scope) setportal(key string, value []byte) {
+	if s.record {
+		s.effects = append(s.effects, effect{portal: true, key: key, value: value})
+	}
+	if i, ok := s.portalkeys[key]; ok && len(key) > 0 {
+		s.portal[i] = value
+		return
+	}
+	s.portalkeys[key] = len(s.portal)
+	s.portal = append(s.portal, value)
+}
+
+// replay registers the effects recorded while rendering cached output in the response
+// scope of ctx, resolving their nonce to the nonce of ctx. Hoisted output that can not be
+// hoisted anymore, because the response is resolved or ctx has no scope, is written to w.
+func replay(ctx context.Context, effects []effect, w io.Writer) error {
+	if len(effects) == 0 {
+		return nil
+	}
+	inplace := []byte{}
+	s := scopeof(ctx)
+	if s != nil {
+		s.mu.Lock()
+	}
+	for _, e := range effects {
+		if e.portal {
+			if s != nil && !s.resolved {
+				s.setportal(e.key, resolvenonce(ctx, e.value))
+			} else {
+				inplace = append(inplace, resolvenonce(ctx, e.value)...)
+			}
+			continue
+		}
+		if s != nil {
+			if s.seen[e.key] {
+				continue
+			}
+			if !s.resolved {
+				s.once(e.key, e.target, resolvenonce(ctx, e.value))
+				continue
+			}
+			s.seen[e.key] = true
+		}
+		if e.target != HoistNone {
+			inplace = append(inplace, resolvenonce(ctx, e.value)...)
+		}
+	}
+	if s != nil {
+		s.mu.Unlock()
+	}
+	_, err := w.Write(inplace)
+	return err
+}
+
+type scopekey struct{}
+
+// WithResponseScope returns a copy of ctx that carries the state of a single response,
+// so that Once nodes rendered with the returned context render once and hoisted nodes
+// are collected. The rendered output must be passed to ResolveHoisted before it is sent.
+func WithResponseScope(ctx context.Context) context.Context {
+	s := &scope{token: rand.Text(), seen: map[string]bool{}, portalkeys: map[string]int{}}
+	return context.WithValue(ctx, scopekey{}, s)
+}
+
+func scopeof(ctx context.Context) *scope {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(scopekey{}).(*scope)
+	return s
+}
+
+// ResolveHoisted replaces the outlets in the output rendered with ctx by the nodes hoisted
+// into them. Hoisted nodes whose outlet is not in the output (e.g. a fragment without a
+// <head> element) are appended to the end. Head portal nodes are placed at the end of the
+// <head> element if there is no HeadOutlet. Only the outlets of the response are replaced,
+// page content that looks like an outlet is left as is. It returns rendered as is if ctx
+// has no response scope.
+func ResolveHoisted(ctx context.Context, rendered []byte) []byte {
+	s := scopeof(ctx)
+	if s == nil {
+		return rendered
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolved = true
+
+	portal := bytes.Join(s.portal, nil)
+	rendered, ok := splice(rendered, s.outlet(portaloutlet), portal)
+	if !ok {
+		portal = append(portal, s.hoisted[HoistHead].Bytes()...)
+		s.hoisted[HoistHead].Reset()
+		s.hoisted[HoistHead].Write(portal)
+	}
+
+	trailing := []byte{}
+	for target, outlet := range outlets {
+		if len(outlet) == 0 {
+			continue
+		}
+		if rendered, ok = splice(rendered, s.outlet(outlet), s.hoisted[target].Bytes()); !ok {
+			trailing = append(trailing, s.hoisted[target].Bytes()...)
+		}
+	}
+	return append(rendered, trailing...)
+}
+
+// splice replaces the first occurrence of outlet in rendered with content and removes the
+// others. It reports false if rendered does not contain the outlet.
+func splice(rendered, outlet, content []byte) ([]byte, bool) {
+	i := bytes.Index(rendered, outlet)
+	if i < 0 {
+		return rendered, false
+	}
+	rest := bytes.ReplaceAll(rendered[i+len(outlet):], outlet, nil)
+	return append(append(rendered[:i:i], content...), rest...), true
+}
+
+var (
+	headoutlet = outlet(HoistHead)
+	bodyoutlet = outlet(HoistBody)
+)
+
+// outlet returns the chunk that marks the position of the given hoist target.
+func outlet(target HoistTarget) DynamicChunk {
+	return marker(outlets[target])
+}
+
+// outlet returns the outlet with the given prefix that is unique to the response.
+func (s *scope) outlet(prefix []byte) []byte {
+	return outletof(prefix, s.token)
+}
+
+func outletof(prefix []byte, token string) []byte {
+	outlet := append(bytes.Clone(prefix), token...)
+	return append(outlet, "-->"...)
+}
+
+// resolveoutlets replaces the outlets of cached output by the outlets of the response
+// of ctx, or removes them if ctx has no response scope.
+func resolveoutlets(ctx context.Context, rendered []byte) []byte {
+	placeholder := []byte(":" + cachedtoken + "-->")
+	if !bytes.Contains(rendered, placeholder) {
+		return rendered
+	}
+	s := scopeof(ctx)
+	for _, prefix := range append(outlets[:], portaloutlet) {
+		if len(prefix) == 0 {
+			continue
+		}
+		var outlet []byte
+		if s != nil {
+			outlet = s.outlet(prefix)
+		}
+		rendered = bytes.ReplaceAll(rendered, outletof(prefix, cachedtoken), outlet)
+	}
+	return rendered
+}
+
+// marker returns a chunk that writes the outlet with the given prefix if the response is
+// not resolved yet.
+func marker(outlet []byte) DynamicChunk {
+	return func(ctx context.Context, w io.Writer) error {
+		s := scopeof(ctx)
+		if s == nil {
+			return nil
+		}
+		s.mu.Lock()
+		resolved := s.resolved
+		s.mu.Unlock()
+		if resolved {
+			return nil
+		}
+		_, err := w.Write(s.outlet(outlet))
+		return err
+	}
+}
+
+/*
+OnceNode renders its node only the first time its key is seen in a response, so that the
+styles and scripts a component needs are not repeated for every instance of the component.
+
+Usage:
+
+	func Tooltip(items ...html.Item) html.Node {
+		return html.Fragment(
+			html.Once("tooltip-style", html.Style(html.RawUnsafe(".tooltip { ... }"))).Hoist(html.HoistHead),
+			html.Div(append(items, html.Class("tooltip"))...),
+		)
+	}
+
+Nodes are only deduplicated while rendering with a context that has a response scope, which
+the server sets up for every page, see WithResponseScope. They are rendered in place every
+time otherwise.
+*/
+type OnceNode struct {
+	key    string
+	node   Node
+	target HoistTarget
+}
+
+// Implements the Item interface.
+func (*OnceNode) Item() {}
+
+// Implements the Node interface.
+func (n *OnceNode) Release() {
+	n.node.Release()
+}
+
+func (n *OnceNode) freeze() {
+	freeze(n.node)
+}
+
+// Moves the node to the end of the <head> or the <body> element of the response, wherever
+// it appears in the tree, and returns it back.
+func (n *OnceNode) Hoist(target HoistTarget) *OnceNode {
+	n.target = target
+	return n
+}
+
+// Implements the Node interface.
+func (n *OnceNode) Render(w ChunkWriter) error {
+	f := formatof(w)
+	snapshot := *f
+	inner := newcw(f)
+	if err := n.node.Render(inner); err != nil {
+		return err
+	}
+	if n.target != HoistNone {
+		// Hoisted nodes do not take up any space where they appear
+		*f = snapshot
+	}
+	chunks := inner.Chunks()
+
+	w.Write(DynamicChunk(func(ctx context.Context, out io.Writer) error {
+		s := scopeof(ctx)
+		if s == nil {
+			return renderchunks(chunks, ctx, out)
+		}
+
+		s.mu.Lock()
+		seen := s.seen[n.key]
+		hoist := n.target != HoistNone && !s.resolved
+		inherited := seen && hoist && s.inherited[n.key]
+		delete(s.inherited, n.key)
+		if !seen && !hoist {
+			s.once(n.key, HoistNone, nil)
+		}
+		s.mu.Unlock()
+
+		switch {
+		case seen && !inherited:
+			return nil
+		case !hoist:
+			return renderchunks(chunks, ctx, out)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := renderchunks(chunks, ctx, buf); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if inherited {
+			s.effects = append(s.effects, effect{key: n.key, target: n.target, value: buf.Bytes()})
+			return nil
+		}
+		if s.seen[n.key] {
+			// Rendered concurrently, e.g. by another async chunk
+			return nil
+		}
+		s.once(n.key, n.target, buf.Bytes())
+		return nil
+	}))
+	return nil
+}
+
+// Once creates a node that renders the given node only the first time the key is seen in
+// a response. Keys are shared by the whole response, so they should be unique to the node.
+func Once(key string, node Node) *OnceNode {
+	return &OnceNode{key: key, node: node}
+}
+
+func renderchunks(chunks []Chunk, ctx context.Context, w io.Writer) error {
+	for _, chunk := range chunks {
+		if err := Render(chunk, ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
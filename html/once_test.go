@@ -0,0 +1,96 @@
+package html_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	tooltip := func(text string) html.Node {
+		return html.Fragment(
+			html.Once("tooltip-style", html.Style(html.RawUnsafe(".tooltip{}"))).Hoist(html.HoistHead),
+			html.Once("tooltip-script", html.Script(html.RawUnsafe("init()"))).Hoist(html.HoistBody),
+			html.Span(html.Class("tooltip"), html.Text(text)),
+		)
+	}
+	page := html.Html(
+		html.Head(html.Title(html.Text("Page"))),
+		html.Body(
+			tooltip("a"),
+			html.Component(func(ctx context.Context) html.Node { return tooltip("b") }),
+			html.Once("divider", html.Hr()),
+			html.Once("divider", html.Hr()),
+			html.Main(),
+		),
+	)
+
+	ctx := html.WithResponseScope(context.Background())
+	rendered, err := renderContext(ctx, page)
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><title>Page</title><style>.tooltip{}</style></head><body><span class="tooltip">a</span><span class="tooltip">b</span><hr><main></main><script>init()</script></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Every response has its own scope
+	ctx = html.WithResponseScope(context.Background())
+	rendered, err = renderContext(ctx, html.Div(tooltip("c")))
+	assert.NoError(err)
+	assert.Equal(
+		`<div><span class="tooltip">c</span></div><style>.tooltip{}</style><script>init()</script>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Without a scope nodes are rendered in place
+	rendered, err = renderContext(context.Background(), html.Head(tooltip("d"), tooltip("e")))
+	assert.NoError(err)
+	assert.Equal(
+		`<head><style>.tooltip{}</style><script>init()</script><span class="tooltip">d</span><style>.tooltip{}</style><script>init()</script><span class="tooltip">e</span></head>`,
+		rendered,
+	)
+}
+
+func TestHoistOutletContent(t *testing.T) {
+	assert := assert.New(t)
+
+	// Page content that contains the outlets is not replaced
+	content := `<!--pacis:hoist:head--><!--pacis:hoist:body--><!--pacis:head-->`
+	page := html.Html(
+		html.Head(html.HeadOutlet(), html.HeadPortal("title", html.Title(html.Text("Page")))),
+		html.Body(
+			html.RawUnsafe(content),
+			html.Once("style", html.Style(html.RawUnsafe(".a{}"))).Hoist(html.HoistHead),
+			html.Once("script", html.Script(html.RawUnsafe("init()"))).Hoist(html.HoistBody),
+		),
+	)
+
+	ctx := html.WithResponseScope(context.Background())
+	rendered, err := renderContext(ctx, page)
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><title>Page</title><style>.a{}</style></head><body>`+content+`<script>init()</script></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Outlets within cached output are the outlets of the response they are written to
+	cached := html.Html(
+		html.Cached(func(context.Context) string { return "head" }, 0, html.Component(func(context.Context) html.Node {
+			return html.Head(html.HeadOutlet())
+		})).WithCache(html.NewCache(1)),
+		html.Body(html.HeadPortal("title", html.Title(html.Text("Page")))),
+	)
+	for range 2 {
+		ctx := html.WithResponseScope(context.Background())
+		rendered, err := renderContext(ctx, cached)
+		assert.NoError(err)
+		assert.Equal(`<html><head><title>Page</title></head><body></body></html>`, string(html.ResolveHoisted(ctx, []byte(rendered))))
+	}
+	rendered, err = renderContext(context.Background(), cached)
+	assert.NoError(err)
+	assert.Equal(`<html><head></head><body><title>Page</title></body></html>`, rendered)
+}
@@ -0,0 +1,173 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Parse reads HTML markup from r and converts it into a Frag of *Element and Text nodes
+// that can be modified and rendered like any other node.
+//
+// Complete documents (starting with a doctype or an <html> tag) are parsed as a whole,
+// anything else is parsed as a fragment in the context of a <body> element. Attributes
+// are preserved in source order, comments are dropped and the contents of <script> and
+// <style> elements are kept as RawUnsafe nodes since they are not HTML-escaped in the source.
+//
+// Usage:
+//
+//	nodes, err := html.Parse(strings.NewReader(`<p class="lead">Hello</p>`))
+//	if err != nil { ... }
+//	p := nodes[0].(*html.Element)
+//	p.AddClass("text-lg")
+func Parse(r io.Reader) (Frag, error) {
+	source, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*html.Node
+	if isdocument(source) {
+		doc, err := html.Parse(bytes.NewReader(source))
+		if err != nil {
+			return nil, err
+		}
+		for child := range doc.ChildNodes() {
+			roots = append(roots, child)
+		}
+	} else {
+		context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		roots, err = html.ParseFragment(bytes.NewReader(source), context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	frag := Frag{}
+	for _, root := range roots {
+		node, err := convert(root, false)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			frag = append(frag, node)
+		}
+	}
+	return frag, nil
+}
+
+func isdocument(source []byte) bool {
+	source = bytes.TrimSpace(source)
+	if len(source) > 0 && source[0] == 0xEF {
+		// Byte order mark
+		source = bytes.TrimPrefix(source, []byte("\xEF\xBB\xBF"))
+	}
+	for _, prefix := range []string{"<!doctype", "<html"} {
+		if len(source) >= len(prefix) && bytes.EqualFold(source[:len(prefix)], []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawtextelements are the elements whose text content is not escaped in the source, the
+// parser keeps their content as a single text node (e.g. <noscript> since scripting is enabled).
+var rawtextelements = map[atom.Atom]bool{
+	atom.Iframe:    true,
+	atom.Noembed:   true,
+	atom.Noframes:  true,
+	atom.Noscript:  true,
+	atom.Plaintext: true,
+	atom.Script:    true,
+	atom.Style:     true,
+	atom.Xmp:       true,
+}
+
+// doctype converts a doctype node, the public and system identifiers of legacy doctypes
+// (e.g. XHTML 1.0) are kept.
+func doctype(n *html.Node) Node {
+	var public, system string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "public":
+			public = attr.Val
+		case "system":
+			system = attr.Val
+		}
+	}
+	if len(public) == 0 && len(system) == 0 {
+		return VoidEl("!DOCTYPE", Attr(n.Data, ""))
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("<!DOCTYPE " + n.Data)
+	if len(public) > 0 {
+		b.WriteString(" PUBLIC " + quoteid(public))
+		if len(system) > 0 {
+			b.WriteString(" " + quoteid(system))
+		}
+	} else {
+		b.WriteString(" SYSTEM " + quoteid(system))
+	}
+	b.WriteString(">")
+	return RawUnsafe(b.String())
+}
+
+// quoteid quotes a doctype identifier, identifiers can't contain the quote they are quoted with.
+func quoteid(id string) string {
+	if strings.Contains(id, `"`) {
+		return "'" + id + "'"
+	}
+	return `"` + id + `"`
+}
+
+// convert turns a parsed html node into a Node, rawtext reports whether the node is
+// a child of an element whose text content is not escaped (e.g. <script>).
+func convert(n *html.Node, rawtext bool) (Node, error) {
+	switch n.Type {
+	case html.TextNode:
+		if rawtext {
+			return RawUnsafe(n.Data), nil
+		}
+		return Text(n.Data), nil
+	case html.DoctypeNode:
+		return doctype(n), nil
+	case html.CommentNode:
+		return nil, nil
+	case html.ElementNode:
+		var el *Element
+		switch n.Namespace {
+		case "svg":
+			el = NSEl(NamespaceSVG, n.Data)
+		case "math":
+			el = NSEl(NamespaceMathML, n.Data)
+		default:
+			el = El(n.Data)
+		}
+		for _, attr := range n.Attr {
+			key := attr.Key
+			if len(attr.Namespace) > 0 {
+				key = attr.Namespace + ":" + key
+			}
+			el.SetAttribute(key, attr.Val)
+		}
+
+		raw := rawtextelements[n.DataAtom]
+		for child := range n.ChildNodes() {
+			node, err := convert(child, raw)
+			if err != nil {
+				return nil, err
+			}
+			if node != nil {
+				el.AppendNode(node)
+			}
+		}
+		return el, nil
+	default:
+		return nil, fmt.Errorf("unexpected html node type %d", n.Type)
+	}
+}
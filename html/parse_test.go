@@ -0,0 +1,81 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(node html.Node) string {
+	cw := html.NewChunkWriter()
+	node.Render(cw)
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		html.Render(chunk, context.Background(), buf)
+	}
+	return buf.String()
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		Source   string
+		Rendered string
+	}{
+		{
+			Source:   `<div class="card" data-slot="card"><p>Hello &amp; welcome</p></div>`,
+			Rendered: `<div class="card" data-slot="card"><p>Hello &amp; welcome</p></div>`,
+		},
+		{
+			Source:   `<img src="/logo.png" alt="Logo"><br>text<!-- comment -->`,
+			Rendered: `<img src="/logo.png" alt="Logo"><br>text`,
+		},
+		{
+			Source:   `<button @click="open = !open" x-bind:class="{ 'active': open }">Toggle</button>`,
+			Rendered: `<button @click="open = !open" x-bind:class="{ 'active': open }">Toggle</button>`,
+		},
+		{
+			Source:   `<script>if (a < b && c) {}</script>`,
+			Rendered: `<script>if (a < b && c) {}</script>`,
+		},
+		{
+			Source:   `<!DOCTYPE html><html><head><title>Page</title></head><body><main></main></body></html>`,
+			Rendered: `<!DOCTYPE html><html><head><title>Page</title></head><body><main></main></body></html>`,
+		},
+		{
+			Source:   `<noscript><img src="/pixel.gif" alt="a &amp; b"></noscript><xmp><b>&amp;</b></xmp><iframe>&lt;p&gt;</iframe>`,
+			Rendered: `<noscript><img src="/pixel.gif" alt="a &amp; b"></noscript><xmp><b>&amp;</b></xmp><iframe>&lt;p&gt;</iframe>`,
+		},
+		{
+			Source:   `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd"><html><head></head><body></body></html>`,
+			Rendered: `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd"><html><head></head><body></body></html>`,
+		},
+	}
+
+	for _, test := range tests {
+		nodes, err := html.Parse(strings.NewReader(test.Source))
+		assert.NoError(err)
+		assert.Equal(test.Rendered, render(nodes))
+	}
+}
+
+func TestParseModify(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes, err := html.Parse(strings.NewReader(`<ul class="list"><li>One</li></ul>`))
+	assert.NoError(err)
+	assert.Len(nodes, 1)
+
+	list, ok := nodes[0].(*html.Element)
+	assert.True(ok)
+	list.AddClass("gap-2")
+	list.SetAttribute("role", "list")
+	list.AppendNode(html.Li(html.Text("Two")))
+
+	assert.Equal(`<ul class="list gap-2" role="list"><li>One</li><li>Two</li></ul>`, render(nodes))
+}
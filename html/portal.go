@@ -0,0 +1,111 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+var portaloutlet = []byte("<!--pacis:head:")
+
+/*
+HeadPortalNode renders its node into the <head> element of the response, at the position of
+the HeadOutlet, wherever it appears in the tree. Nodes are deduplicated by their key: the
+node registered last wins while the position of the first registration is kept, so a nested
+component can override the title or the canonical link of its page.
+
+Usage:
+
+	func ProductCard(ctx context.Context) html.Node {
+		product := ...
+		return html.Fragment(
+			html.HeadPortal("canonical", html.Link(html.Rel("canonical"), html.Href(product.URL))),
+			html.HeadPortal("preload:"+product.Image, html.Link(html.Rel("preload"), html.As("image"), html.Href(product.Image))),
+			html.Article(...),
+		)
+	}
+
+Like Once, the nodes are only collected while rendering with a context that has a response
+scope, see WithResponseScope. They are rendered in place otherwise.
+*/
+type HeadPortalNode struct {
+	key  string
+	node Node
+}
+
+// Implements the Item interface.
+func (*HeadPortalNode) Item() {}
+
+// Implements the Node interface.
+func (n *HeadPortalNode) Release() {
+	n.node.Release()
+}
+
+func (n *HeadPortalNode) freeze() {
+	freeze(n.node)
+}
+
+// Implements the Node interface.
+func (n *HeadPortalNode) Render(w ChunkWriter) error {
+	f := formatof(w)
+	snapshot := *f
+	inner := newcw(f)
+	if err := n.node.Render(inner); err != nil {
+		return err
+	}
+	*f = snapshot
+	chunks := inner.Chunks()
+
+	w.Write(DynamicChunk(func(ctx context.Context, out io.Writer) error {
+		s := scopeof(ctx)
+		if s == nil {
+			return renderchunks(chunks, ctx, out)
+		}
+		s.mu.Lock()
+		resolved := s.resolved
+		s.mu.Unlock()
+		if resolved {
+			return renderchunks(chunks, ctx, out)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := renderchunks(chunks, ctx, buf); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.setportal(n.key, buf.Bytes())
+		return nil
+	}))
+	return nil
+}
+
+// HeadPortal creates a node that renders the given node into the <head> element of the
+// response, replacing the node previously registered with the same key. Nodes with an
+// empty key are never replaced.
+func HeadPortal(key string, node Node) *HeadPortalNode {
+	return &HeadPortalNode{key: key, node: node}
+}
+
+// HeadOutlet marks the position in the <head> element where the head portal nodes are
+// flushed, the server places it in the head argument of layouts. Without an outlet the
+// nodes are placed at the end of the <head> element.
+func HeadOutlet() Node {
+	return outletnode{}
+}
+
+type outletnode struct{}
+
+// Implements the Item interface.
+func (outletnode) Item() {}
+
+// Implements the Node interface.
+func (outletnode) Release() {}
+
+// Implements the Node interface.
+func (outletnode) Render(w ChunkWriter) error {
+	w.Write(headportalmarker)
+	return nil
+}
+
+var headportalmarker = marker(portaloutlet)
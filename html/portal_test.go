@@ -0,0 +1,88 @@
+package html_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadPortal(t *testing.T) {
+	assert := assert.New(t)
+
+	card := html.Component(func(ctx context.Context) html.Node {
+		return html.Fragment(
+			html.HeadPortal("title", html.Title(html.Text("Product"))),
+			html.HeadPortal("", html.Link(html.Rel("preload"), html.Href("/a.png"))),
+			html.Article(html.Text("card")),
+		)
+	})
+	page := func(outlet bool) html.Node {
+		return html.Html(
+			html.Head(
+				html.Meta(html.Charset("UTF-8")),
+				html.If(outlet, html.HeadOutlet()).(html.Node),
+				html.HeadPortal("title", html.Title(html.Text("Page"))),
+				html.HeadPortal("canonical", html.Link(html.Rel("canonical"), html.Href("/"))),
+			),
+			html.Body(card),
+		)
+	}
+
+	ctx := html.WithResponseScope(context.Background())
+	rendered, err := renderContext(ctx, page(true))
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><meta charset="UTF-8"><title>Product</title><link rel="canonical" href="/"><link rel="preload" href="/a.png"></head><body><article>card</article></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Without an outlet the nodes are placed at the end of the head
+	ctx = html.WithResponseScope(context.Background())
+	rendered, err = renderContext(ctx, page(false))
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><meta charset="UTF-8"><title>Product</title><link rel="canonical" href="/"><link rel="preload" href="/a.png"></head><body><article>card</article></body></html>`,
+		string(html.ResolveHoisted(ctx, []byte(rendered))),
+	)
+
+	// Without a scope nodes are rendered in place
+	rendered, err = renderContext(context.Background(), page(true))
+	assert.NoError(err)
+	assert.Equal(
+		`<html><head><meta charset="UTF-8"><title>Page</title><link rel="canonical" href="/"></head><body><title>Product</title><link rel="preload" href="/a.png"><article>card</article></body></html>`,
+		rendered,
+	)
+}
+
+func TestCachedHeadPortal(t *testing.T) {
+	assert := assert.New(t)
+
+	renders := 0
+	node := html.Html(
+		html.Head(html.HeadOutlet(), html.HeadPortal("title", html.Title(html.Text("Page")))),
+		html.Body(
+			html.Cached(func(context.Context) string { return "product" }, 0, html.Component(func(context.Context) html.Node {
+				renders++
+				return html.Fragment(
+					html.HeadPortal("title", html.Title(html.Text("Product"))),
+					html.HeadPortal("canonical", html.Link(html.Rel("canonical"), html.Href("/product"))),
+					html.Article(html.Text("card")),
+				)
+			})).WithCache(html.NewCache(1)),
+		),
+	)
+
+	// Cache hits replay the head portal nodes of the entry
+	for range 2 {
+		ctx := html.WithResponseScope(context.Background())
+		rendered, err := renderContext(ctx, node)
+		assert.NoError(err)
+		assert.Equal(
+			`<html><head><title>Product</title><link rel="canonical" href="/product"></head><body><article>card</article></body></html>`,
+			string(html.ResolveHoisted(ctx, []byte(rendered))),
+		)
+	}
+	assert.Equal(1, renders)
+}
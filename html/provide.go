@@ -0,0 +1,81 @@
+package html
+
+import (
+	"context"
+	"io"
+)
+
+type providerkey[T any] struct{}
+
+/*
+ProviderNode makes a value available to the components in its subtree during a render,
+see Provide and Use.
+*/
+type ProviderNode[T any] struct {
+	value T
+	nodes Frag
+}
+
+// Implements the Item interface.
+func (*ProviderNode[T]) Item() {}
+
+// Implements the Node interface.
+func (p *ProviderNode[T]) Release() {
+	p.nodes.Release()
+}
+
+func (p *ProviderNode[T]) freeze() {
+	freeze(p.nodes)
+}
+
+// Implements the Node interface.
+//
+// The static chunks of the subtree are written as they are while the dynamic chunks
+// (e.g. components) are rendered with a context that carries the value.
+func (p *ProviderNode[T]) Render(w ChunkWriter) error {
+	inner := newcw(formatof(w))
+	if err := p.nodes.Render(inner); err != nil {
+		return err
+	}
+
+	for _, chunk := range inner.Chunks() {
+		dynamic, ok := chunk.(DynamicChunk)
+		if !ok {
+			w.Write(chunk)
+			continue
+		}
+		w.Write(DynamicChunk(func(ctx context.Context, out io.Writer) error {
+			return dynamic(context.WithValue(ctx, providerkey[T]{}, p.value), out)
+		}))
+	}
+	return nil
+}
+
+/*
+Provide makes the value available to every component rendered within the children, which
+read it with Use[T]. Values are looked up by their type, so a value provided further down
+the tree shadows the values of the same type provided by its ancestors.
+
+Usage:
+
+	type Theme string
+
+	html.Provide(Theme("dark"),
+		html.Component(Toolbar),
+	)
+
+	func Toolbar(ctx context.Context) html.Node {
+		theme, _ := html.Use[Theme](ctx)
+		return html.Div(html.Data("theme", string(theme)))
+	}
+*/
+func Provide[T any](value T, children ...Node) *ProviderNode[T] {
+	return &ProviderNode[T]{value: value, nodes: Frag(children)}
+}
+
+// Use returns the value of type T provided by the closest ancestor Provide node of the
+// component that is being rendered with ctx. It reports false if no ancestor provides it.
+func Use[T any](ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(providerkey[T]{}).(T)
+	return value, ok
+}
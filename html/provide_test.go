@@ -0,0 +1,43 @@
+package html_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+type theme string
+
+func TestProvide(t *testing.T) {
+	assert := assert.New(t)
+
+	badge := html.Component(func(ctx context.Context) html.Node {
+		value, ok := html.Use[theme](ctx)
+		if !ok {
+			return html.Span(html.Text("none"))
+		}
+		return html.Span(html.Text(string(value)))
+	})
+	user := html.Component(func(ctx context.Context) html.Node {
+		name, _ := html.Use[string](ctx)
+		return html.Fragment(html.B(html.Text(name)), badge)
+	})
+
+	node := html.Div(
+		badge,
+		html.Provide(theme("dark"),
+			html.P(html.Text("static")),
+			html.Fragment(badge, html.Component(func(ctx context.Context) html.Node {
+				// Components nested in components
+				return html.Provide("Jane", user)
+			})),
+			html.Provide(theme("light"), badge),
+		),
+	)
+
+	rendered, err := renderContext(context.Background(), node)
+	assert.NoError(err)
+	assert.Equal(`<div><span>none</span><p>static</p><span>dark</span><b>Jane</b><span>dark</span><span>light</span></div>`, rendered)
+}
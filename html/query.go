@@ -0,0 +1,529 @@
+package html
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipChildren is used as a return value from the function passed to Walk to indicate
+// that the children of the current node are to be skipped. It is not returned as an error by Walk.
+var SkipChildren = errors.New("skip children")
+
+// SkipAll is used as a return value from the function passed to Walk to indicate
+// that all remaining nodes are to be skipped. It is not returned as an error by Walk.
+var SkipAll = errors.New("skip all")
+
+// Walk traverses the node tree rooted at node in depth-first order, calling fn for each node,
+// including the root. Children of elements and fragments are visited, components are not
+// descended into since they need a render context to produce their nodes.
+//
+// If fn returns SkipChildren, the children of the current node are skipped, if it returns
+// SkipAll, the walk stops. Any other error stops the walk and is returned by Walk.
+func Walk(node Node, fn func(Node) error) error {
+	if err := walk(node, fn); err != nil && err != SkipAll {
+		return err
+	}
+	return nil
+}
+
+func walk(node Node, fn func(Node) error) error {
+	if err := fn(node); err != nil {
+		if err == SkipChildren {
+			return nil
+		}
+		return err
+	}
+
+	var children []Node
+	switch node := node.(type) {
+	case *Element:
+		children = node.nodes
+	case Frag:
+		children = node
+	}
+	for _, child := range children {
+		if err := walk(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childelements returns the element children of a node, flattening any fragments in between.
+func childelements(nodes []Node) []*Element {
+	children := []*Element{}
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *Element:
+			children = append(children, node)
+		case Frag:
+			children = append(children, childelements(node)...)
+		}
+	}
+	return children
+}
+
+type attrselector struct {
+	key   string
+	op    string
+	value string
+}
+
+func (s attrselector) match(el *Element) bool {
+	var value string
+	var found bool
+	for _, attr := range el.attributelist {
+		if attr.Key == s.key {
+			value, found = attr.Value, true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	switch s.op {
+	case "":
+		return true
+	case "=":
+		return value == s.value
+	case "~=":
+		for _, field := range strings.Fields(value) {
+			if field == s.value {
+				return true
+			}
+		}
+		return false
+	case "^=":
+		return len(s.value) > 0 && strings.HasPrefix(value, s.value)
+	case "$=":
+		return len(s.value) > 0 && strings.HasSuffix(value, s.value)
+	case "*=":
+		return len(s.value) > 0 && strings.Contains(value, s.value)
+	case "|=":
+		return value == s.value || strings.HasPrefix(value, s.value+"-")
+	default:
+		return false
+	}
+}
+
+// compound is a sequence of simple selectors that all apply to a single element, e.g. div.card[data-slot].
+type compound struct {
+	tag        string
+	id         string
+	classes    []string
+	attrs      []attrselector
+	firstchild bool
+	lastchild  bool
+}
+
+// position describes where an element is located among its parent's element children.
+type position struct {
+	first bool
+	last  bool
+}
+
+func (c *compound) match(el *Element, pos position) bool {
+	if len(c.tag) > 0 && c.tag != "*" && !strings.EqualFold(c.tag, el.Tag()) {
+		return false
+	}
+	if len(c.id) > 0 && el.GetAttribute("id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(el.GetAttribute("class"))
+		for _, class := range c.classes {
+			found := false
+			for _, candidate := range classes {
+				if candidate == class {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		if !attr.match(el) {
+			return false
+		}
+	}
+	if c.firstchild && !pos.first {
+		return false
+	}
+	if c.lastchild && !pos.last {
+		return false
+	}
+	return true
+}
+
+const (
+	descendant = ' '
+	child      = '>'
+)
+
+// complexselector is a chain of compound selectors joined by combinators. combinators[i]
+// sits between compounds[i] and compounds[i+1].
+type complexselector struct {
+	compounds   []compound
+	combinators []byte
+}
+
+// step is an element on the path from the query root to the element being matched.
+type step struct {
+	el  *Element
+	pos position
+}
+
+// match reports whether the last step of the path matches the selector, path holds the
+// element being matched and all of its ancestors up to the query root.
+func (s *complexselector) match(path []step) bool {
+	return s.matchat(len(s.compounds)-1, path)
+}
+
+func (s *complexselector) matchat(i int, path []step) bool {
+	current := path[len(path)-1]
+	if !s.compounds[i].match(current.el, current.pos) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	ancestors := path[:len(path)-1]
+	switch s.combinators[i-1] {
+	case child:
+		return len(ancestors) > 0 && s.matchat(i-1, ancestors)
+	default:
+		for j := len(ancestors); j > 0; j-- {
+			if s.matchat(i-1, ancestors[:j]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Selector is a compiled CSS selector that can be matched against elements.
+// It supports type, universal, id, class and attribute selectors, the descendant
+// and child combinators, the :first-child and :last-child pseudo classes and
+// comma separated selector lists.
+type Selector struct {
+	source string
+	list   []complexselector
+}
+
+// String returns the source of the selector.
+func (s *Selector) String() string {
+	return s.source
+}
+
+func (s *Selector) match(path []step) bool {
+	for i := range s.list {
+		if s.list[i].match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+type selectorparser struct {
+	source string
+	pos    int
+}
+
+func (p *selectorparser) errorf(format string, a ...any) error {
+	return fmt.Errorf("invalid selector %q at offset %d: %s", p.source, p.pos, fmt.Sprintf(format, a...))
+}
+
+func (p *selectorparser) eof() bool {
+	return p.pos >= len(p.source)
+}
+
+func (p *selectorparser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.source[p.pos]
+}
+
+func (p *selectorparser) whitespace() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func isidentchar(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ident parses an identifier, allowing backslash escapes such as `sm\:flex`.
+func (p *selectorparser) ident() (string, error) {
+	buf := new(strings.Builder)
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '\\':
+			if p.pos+1 >= len(p.source) {
+				return "", p.errorf("unterminated escape")
+			}
+			buf.WriteByte(p.source[p.pos+1])
+			p.pos += 2
+		case isidentchar(c):
+			buf.WriteByte(c)
+			p.pos++
+		default:
+			if buf.Len() == 0 {
+				return "", p.errorf("expected identifier")
+			}
+			return buf.String(), nil
+		}
+	}
+	if buf.Len() == 0 {
+		return "", p.errorf("expected identifier")
+	}
+	return buf.String(), nil
+}
+
+func (p *selectorparser) attr() (attrselector, error) {
+	// Skip the opening bracket
+	p.pos++
+	p.whitespace()
+
+	var sel attrselector
+	key, err := p.ident()
+	if err != nil {
+		return sel, err
+	}
+	sel.key = key
+	p.whitespace()
+
+	switch p.peek() {
+	case ']':
+		p.pos++
+		return sel, nil
+	case '=':
+		sel.op = "="
+		p.pos++
+	case '~', '^', '$', '*', '|':
+		if p.pos+1 >= len(p.source) || p.source[p.pos+1] != '=' {
+			return sel, p.errorf("expected attribute operator")
+		}
+		sel.op = p.source[p.pos : p.pos+2]
+		p.pos += 2
+	default:
+		return sel, p.errorf("unexpected character %q in attribute selector", p.peek())
+	}
+	p.whitespace()
+
+	switch quote := p.peek(); quote {
+	case '"', '\'':
+		end := strings.IndexByte(p.source[p.pos+1:], quote)
+		if end < 0 {
+			return sel, p.errorf("unterminated string")
+		}
+		sel.value = p.source[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	default:
+		value, err := p.ident()
+		if err != nil {
+			return sel, err
+		}
+		sel.value = value
+	}
+	p.whitespace()
+
+	if p.peek() != ']' {
+		return sel, p.errorf("expected ]")
+	}
+	p.pos++
+	return sel, nil
+}
+
+func (p *selectorparser) compound() (compound, error) {
+	var c compound
+	start := p.pos
+
+	if p.peek() == '*' {
+		c.tag = "*"
+		p.pos++
+	} else if isidentchar(p.peek()) {
+		tag, err := p.ident()
+		if err != nil {
+			return c, err
+		}
+		c.tag = tag
+	}
+
+	for !p.eof() {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			id, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			c.id = id
+		case '.':
+			p.pos++
+			class, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			attr, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attr)
+		case ':':
+			p.pos++
+			name, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			switch strings.ToLower(name) {
+			case "first-child":
+				c.firstchild = true
+			case "last-child":
+				c.lastchild = true
+			default:
+				return c, p.errorf("unsupported pseudo class :%s", name)
+			}
+		default:
+			if p.pos == start {
+				return c, p.errorf("unexpected character %q", p.peek())
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, p.errorf("expected selector")
+	}
+	return c, nil
+}
+
+func (p *selectorparser) complex() (complexselector, error) {
+	var s complexselector
+	p.whitespace()
+
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return s, err
+		}
+		s.compounds = append(s.compounds, c)
+
+		spaced := p.whitespace()
+		if p.eof() || p.peek() == ',' {
+			return s, nil
+		}
+		if p.peek() == '>' {
+			p.pos++
+			p.whitespace()
+			s.combinators = append(s.combinators, child)
+		} else if spaced {
+			s.combinators = append(s.combinators, descendant)
+		} else {
+			return s, p.errorf("unexpected character %q", p.peek())
+		}
+	}
+}
+
+// CompileSelector parses a CSS selector into a Selector that can be used to query element trees.
+func CompileSelector(source string) (*Selector, error) {
+	p := &selectorparser{source: source}
+	sel := &Selector{source: source}
+
+	for {
+		complex, err := p.complex()
+		if err != nil {
+			return nil, err
+		}
+		sel.list = append(sel.list, complex)
+		if p.eof() {
+			return sel, nil
+		}
+		// Skip the comma
+		p.pos++
+	}
+}
+
+// MustCompileSelector is like CompileSelector but panics if the selector cannot be parsed.
+func MustCompileSelector(source string) *Selector {
+	sel, err := CompileSelector(source)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+// query calls fn for each descendant of root matching the selector, in document order,
+// until fn returns false.
+func query(root *Element, sel *Selector, fn func(*Element) bool) {
+	path := []step{{el: root, pos: position{first: true, last: true}}}
+
+	var visit func(nodes []Node) bool
+	visit = func(nodes []Node) bool {
+		children := childelements(nodes)
+		for i, child := range children {
+			path = append(path, step{el: child, pos: position{first: i == 0, last: i == len(children)-1}})
+			if sel.match(path) && !fn(child) {
+				return false
+			}
+			if !visit(child.nodes) {
+				return false
+			}
+			path = path[:len(path)-1]
+		}
+		return true
+	}
+	visit(root.nodes)
+}
+
+// QuerySelector returns the first descendant of the element that matches the given CSS selector,
+// or nil if there is no match. The element itself is not matched but it is considered as an
+// ancestor, so `div > p` finds the <p> children of a <div>. It panics if the selector is invalid.
+//
+// Usage:
+//
+//	input := field.QuerySelector("[data-slot=input]")
+//	if input != nil {
+//		input.SetAttribute("aria-describedby", id)
+//	}
+func (e *Element) QuerySelector(selector string) *Element {
+	return e.QuerySelectorCompiled(MustCompileSelector(selector))
+}
+
+// QuerySelectorAll returns all of the descendants of the element that match the given
+// CSS selector in document order. It panics if the selector is invalid.
+func (e *Element) QuerySelectorAll(selector string) []*Element {
+	return e.QuerySelectorAllCompiled(MustCompileSelector(selector))
+}
+
+// QuerySelectorCompiled is like QuerySelector but uses an already compiled selector.
+func (e *Element) QuerySelectorCompiled(sel *Selector) *Element {
+	var found *Element
+	query(e, sel, func(el *Element) bool {
+		found = el
+		return false
+	})
+	return found
+}
+
+// QuerySelectorAllCompiled is like QuerySelectorAll but uses an already compiled selector.
+func (e *Element) QuerySelectorAllCompiled(sel *Selector) []*Element {
+	found := []*Element{}
+	query(e, sel, func(el *Element) bool {
+		found = append(found, el)
+		return true
+	})
+	return found
+}
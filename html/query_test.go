@@ -0,0 +1,105 @@
+package html_test
+
+import (
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func tree() *html.Element {
+	return html.Div(
+		html.ID("root"),
+
+		html.Div(
+			html.Role("group"),
+			html.Data("slot", "field"),
+
+			html.Label(html.Class("font-medium sm:text-sm"), html.Text("Email")),
+			html.Input(html.Data("slot", "input"), html.Type("email")),
+		),
+		html.Fragment(
+			html.Ul(
+				html.Li(html.Class("item first"), html.Text("One")),
+				html.Li(html.Class("item"), html.Text("Two")),
+				html.Li(html.Class("item last"), html.Text("Three")),
+			),
+		),
+		html.Button(html.Data("slot", "dialog-close"), html.Span(html.Text("Close"))),
+	)
+}
+
+func TestQuerySelector(t *testing.T) {
+	assert := assert.New(t)
+	root := tree()
+
+	tests := []struct {
+		Selector string
+		N        int
+	}{
+		{"li", 3},
+		{"*", 9},
+		{"#root", 0},
+		{"div > label", 1},
+		{"#root > li", 0},
+		{"#root li", 3},
+		{"ul > li:first-child", 1},
+		{"li:last-child.last", 1},
+		{"li.item", 3},
+		{".item.first", 1},
+		{`.sm\:text-sm`, 1},
+		{"[data-slot=dialog-close]", 1},
+		{`[data-slot="input"][type]`, 1},
+		{"[data-slot^=dialog]", 1},
+		{"[class~=last]", 1},
+		{"[data-slot=field] [data-slot=input]", 1},
+		{"button span, label", 2},
+		{"div:first-child", 1},
+	}
+
+	for _, test := range tests {
+		assert.Len(root.QuerySelectorAll(test.Selector), test.N, test.Selector)
+	}
+
+	input := root.QuerySelector("[data-slot=field] [data-slot=input]")
+	assert.NotNil(input)
+	input.SetAttribute("aria-describedby", "email-description")
+	assert.Equal("email-description", input.GetAttribute("aria-describedby"))
+
+	assert.Nil(root.QuerySelector("table"))
+	assert.Panics(func() { root.QuerySelector("div >") })
+	assert.Panics(func() { root.QuerySelector("li:nth-child(2)") })
+}
+
+func TestWalk(t *testing.T) {
+	assert := assert.New(t)
+
+	elements := 0
+	texts := 0
+	err := html.Walk(tree(), func(node html.Node) error {
+		switch node := node.(type) {
+		case *html.Element:
+			elements++
+			if node.Tag() == "ul" {
+				return html.SkipChildren
+			}
+		case html.Text:
+			texts++
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(7, elements)
+	assert.Equal(2, texts)
+
+	visited := 0
+	err = html.Walk(tree(), func(node html.Node) error {
+		visited++
+		if _, ok := node.(*html.Element); ok && visited > 2 {
+			return html.SkipAll
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(3, visited)
+}
@@ -0,0 +1,765 @@
+// Package svg provides constructors for SVG elements and attributes that can be used
+// together with the html package. SVG elements keep the case of their tag and attribute
+// names (e.g. linearGradient, viewBox) and self close when they have no children.
+//
+// Example usage:
+//
+//	svg.Svg(
+//		svg.ViewBox(0, 0, 24, 24),
+//		svg.Fill("none"),
+//		svg.Stroke("currentColor"),
+//
+//		svg.Path(svg.D("M5 12h14")),
+//		svg.Polyline(svg.Points(svg.Point{X: 12, Y: 5}, svg.Point{X: 19, Y: 12}, svg.Point{X: 12, Y: 19})),
+//	)
+package svg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// Namespace is the XML namespace of SVG elements, used as the value of the xmlns attribute.
+const Namespace = "http://www.w3.org/2000/svg"
+
+// El creates a new SVG element with the given tag name, see html.El.
+func El(name string, items ...html.Item) *html.Element {
+	return html.NSEl(html.NamespaceSVG, name, items...)
+}
+
+// Elements
+
+/*
+# The Anchor element
+
+The <a> SVG element creates a hyperlink to other web pages, files, locations in the same page, email addresses, or any other URL. It is very similar to HTML's <a> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/a
+*/
+func A(items ...html.Item) *html.Element { return El("a", items...) }
+
+/*
+# The Animate element
+
+The <animate> SVG element provides a way to animate an attribute of an element over time.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/animate
+*/
+func Animate(items ...html.Item) *html.Element { return El("animate", items...) }
+
+/*
+# The Animate Motion element
+
+The <animateMotion> SVG element provides a way to define how an element moves along a motion path.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/animateMotion
+*/
+func AnimateMotion(items ...html.Item) *html.Element { return El("animateMotion", items...) }
+
+/*
+# The Animate Transform element
+
+The <animateTransform> SVG element animates a transformation attribute on its target element, thereby allowing animations to control translation, scaling, rotation, and/or skewing.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/animateTransform
+*/
+func AnimateTransform(items ...html.Item) *html.Element { return El("animateTransform", items...) }
+
+/*
+# The Circle element
+
+The <circle> SVG element is an SVG basic shape, used to draw circles based on a center point and a radius.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/circle
+*/
+func Circle(items ...html.Item) *html.Element { return El("circle", items...) }
+
+/*
+# The Clip Path element
+
+The <clipPath> SVG element defines a clipping path, to be used by the clip-path property.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/clipPath
+*/
+func ClipPath(items ...html.Item) *html.Element { return El("clipPath", items...) }
+
+/*
+# The Definitions element
+
+The <defs> SVG element is used to store graphical objects that will be used at a later time. Objects created inside a <defs> element are not rendered directly.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/defs
+*/
+func Defs(items ...html.Item) *html.Element { return El("defs", items...) }
+
+/*
+# The Description element
+
+The <desc> SVG element provides an accessible, long-text description of any SVG container element or graphics element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/desc
+*/
+func Desc(items ...html.Item) *html.Element { return El("desc", items...) }
+
+/*
+# The Ellipse element
+
+The <ellipse> SVG element is an SVG basic shape, used to create ellipses based on a center coordinate, and both their x and y radius.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/ellipse
+*/
+func Ellipse(items ...html.Item) *html.Element { return El("ellipse", items...) }
+
+/*
+# The Blend filter primitive
+
+The <feBlend> SVG filter primitive composes two objects together ruled by a certain blending mode.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feBlend
+*/
+func FeBlend(items ...html.Item) *html.Element { return El("feBlend", items...) }
+
+/*
+# The Color Matrix filter primitive
+
+The <feColorMatrix> SVG filter element changes colors based on a transformation matrix.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feColorMatrix
+*/
+func FeColorMatrix(items ...html.Item) *html.Element { return El("feColorMatrix", items...) }
+
+/*
+# The Component Transfer filter primitive
+
+The <feComponentTransfer> SVG filter primitive performs color-component-wise remapping of data for each pixel.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feComponentTransfer
+*/
+func FeComponentTransfer(items ...html.Item) *html.Element {
+	return El("feComponentTransfer", items...)
+}
+
+/*
+# The Composite filter primitive
+
+The <feComposite> SVG filter primitive performs the combination of two input images pixel-wise in image space using one of the Porter-Duff compositing operations.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feComposite
+*/
+func FeComposite(items ...html.Item) *html.Element { return El("feComposite", items...) }
+
+/*
+# The Convolve Matrix filter primitive
+
+The <feConvolveMatrix> SVG filter primitive applies a matrix convolution filter effect.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feConvolveMatrix
+*/
+func FeConvolveMatrix(items ...html.Item) *html.Element { return El("feConvolveMatrix", items...) }
+
+/*
+# The Diffuse Lighting filter primitive
+
+The <feDiffuseLighting> SVG filter primitive lights an image using the alpha channel as a bump map.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feDiffuseLighting
+*/
+func FeDiffuseLighting(items ...html.Item) *html.Element { return El("feDiffuseLighting", items...) }
+
+/*
+# The Displacement Map filter primitive
+
+The <feDisplacementMap> SVG filter primitive uses the pixel values from the image from in2 to spatially displace the image from in.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feDisplacementMap
+*/
+func FeDisplacementMap(items ...html.Item) *html.Element { return El("feDisplacementMap", items...) }
+
+/*
+# The Distant Light filter primitive
+
+The <feDistantLight> SVG element defines a distant light source that can be used within a lighting filter primitive.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feDistantLight
+*/
+func FeDistantLight(items ...html.Item) *html.Element { return El("feDistantLight", items...) }
+
+/*
+# The Drop Shadow filter primitive
+
+The <feDropShadow> SVG filter primitive creates a drop shadow of the input image.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feDropShadow
+*/
+func FeDropShadow(items ...html.Item) *html.Element { return El("feDropShadow", items...) }
+
+/*
+# The Flood filter primitive
+
+The <feFlood> SVG filter primitive fills the filter subregion with the color and opacity defined by flood-color and flood-opacity.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feFlood
+*/
+func FeFlood(items ...html.Item) *html.Element { return El("feFlood", items...) }
+
+/*
+# The Alpha Transfer Function element
+
+The <feFuncA> SVG filter primitive defines the transfer function for the alpha component of the input graphic of its parent <feComponentTransfer> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feFuncA
+*/
+func FeFuncA(items ...html.Item) *html.Element { return El("feFuncA", items...) }
+
+/*
+# The Blue Transfer Function element
+
+The <feFuncB> SVG filter primitive defines the transfer function for the blue component of the input graphic of its parent <feComponentTransfer> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feFuncB
+*/
+func FeFuncB(items ...html.Item) *html.Element { return El("feFuncB", items...) }
+
+/*
+# The Green Transfer Function element
+
+The <feFuncG> SVG filter primitive defines the transfer function for the green component of the input graphic of its parent <feComponentTransfer> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feFuncG
+*/
+func FeFuncG(items ...html.Item) *html.Element { return El("feFuncG", items...) }
+
+/*
+# The Red Transfer Function element
+
+The <feFuncR> SVG filter primitive defines the transfer function for the red component of the input graphic of its parent <feComponentTransfer> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feFuncR
+*/
+func FeFuncR(items ...html.Item) *html.Element { return El("feFuncR", items...) }
+
+/*
+# The Gaussian Blur filter primitive
+
+The <feGaussianBlur> SVG filter primitive blurs the input image by the amount specified in stdDeviation, which defines the bell-curve.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feGaussianBlur
+*/
+func FeGaussianBlur(items ...html.Item) *html.Element { return El("feGaussianBlur", items...) }
+
+/*
+# The Image filter primitive
+
+The <feImage> SVG filter primitive fetches image data from an external source and provides the pixel data as output.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feImage
+*/
+func FeImage(items ...html.Item) *html.Element { return El("feImage", items...) }
+
+/*
+# The Merge filter primitive
+
+The <feMerge> SVG element allows filter effects to be applied concurrently instead of sequentially.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feMerge
+*/
+func FeMerge(items ...html.Item) *html.Element { return El("feMerge", items...) }
+
+/*
+# The Merge Node element
+
+The <feMergeNode> SVG element takes the result of another filter to be processed by its parent <feMerge>.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feMergeNode
+*/
+func FeMergeNode(items ...html.Item) *html.Element { return El("feMergeNode", items...) }
+
+/*
+# The Morphology filter primitive
+
+The <feMorphology> SVG filter primitive is used to erode or dilate the input image. Its usefulness lies especially in fattening or thinning effects.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feMorphology
+*/
+func FeMorphology(items ...html.Item) *html.Element { return El("feMorphology", items...) }
+
+/*
+# The Offset filter primitive
+
+The <feOffset> SVG filter primitive enables offsetting an input image relative to its current position in the image space by a specified vector.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feOffset
+*/
+func FeOffset(items ...html.Item) *html.Element { return El("feOffset", items...) }
+
+/*
+# The Point Light filter primitive
+
+The <fePointLight> SVG element defines a light source which allows to create a point light effect.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/fePointLight
+*/
+func FePointLight(items ...html.Item) *html.Element { return El("fePointLight", items...) }
+
+/*
+# The Specular Lighting filter primitive
+
+The <feSpecularLighting> SVG filter primitive lights a source graphic using the alpha channel as a bump map.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feSpecularLighting
+*/
+func FeSpecularLighting(items ...html.Item) *html.Element { return El("feSpecularLighting", items...) }
+
+/*
+# The Spot Light filter primitive
+
+The <feSpotLight> SVG element defines a light source that can be used to create a spotlight effect.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feSpotLight
+*/
+func FeSpotLight(items ...html.Item) *html.Element { return El("feSpotLight", items...) }
+
+/*
+# The Tile filter primitive
+
+The <feTile> SVG filter primitive allows to fill a target rectangle with a repeated, tiled pattern of an input image.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feTile
+*/
+func FeTile(items ...html.Item) *html.Element { return El("feTile", items...) }
+
+/*
+# The Turbulence filter primitive
+
+The <feTurbulence> SVG filter primitive creates an image using the Perlin turbulence function.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/feTurbulence
+*/
+func FeTurbulence(items ...html.Item) *html.Element { return El("feTurbulence", items...) }
+
+/*
+# The Filter element
+
+The <filter> SVG element defines a custom filter effect by grouping atomic filter primitives. It is never rendered itself, but must be used by the filter attribute on SVG elements, or the filter CSS property for SVG/HTML elements.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/filter
+*/
+func Filter(items ...html.Item) *html.Element { return El("filter", items...) }
+
+/*
+# The Foreign Object element
+
+The <foreignObject> SVG element includes elements from a different XML namespace. In the context of a browser, it is most likely (X)HTML.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/foreignObject
+*/
+func ForeignObject(items ...html.Item) *html.Element { return El("foreignObject", items...) }
+
+/*
+# The Group element
+
+The <g> SVG element is a container used to group other SVG elements. Transformations applied to the <g> element are performed on its child elements, and its attributes are inherited by its children.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/g
+*/
+func G(items ...html.Item) *html.Element { return El("g", items...) }
+
+/*
+# The Image element
+
+The <image> SVG element includes images inside SVG documents. It can display raster image files or other SVG files.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/image
+*/
+func Image(items ...html.Item) *html.Element { return El("image", items...) }
+
+/*
+# The Line element
+
+The <line> SVG element is an SVG basic shape used to create a line connecting two points.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/line
+*/
+func Line(items ...html.Item) *html.Element { return El("line", items...) }
+
+/*
+# The Linear Gradient element
+
+The <linearGradient> SVG element lets authors define linear gradients to apply to other SVG elements.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/linearGradient
+*/
+func LinearGradient(items ...html.Item) *html.Element { return El("linearGradient", items...) }
+
+/*
+# The Marker element
+
+The <marker> SVG element defines a graphic used for drawing arrowheads or polymarkers on a given <path>, <line>, <polyline> or <polygon> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/marker
+*/
+func Marker(items ...html.Item) *html.Element { return El("marker", items...) }
+
+/*
+# The Mask element
+
+The <mask> SVG element defines a mask for compositing the current object into the background. A mask is used/referenced using the mask property and CSS mask-image property.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/mask
+*/
+func Mask(items ...html.Item) *html.Element { return El("mask", items...) }
+
+/*
+# The Metadata element
+
+The <metadata> SVG element adds metadata to SVG content. Metadata is structured information about data.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/metadata
+*/
+func Metadata(items ...html.Item) *html.Element { return El("metadata", items...) }
+
+/*
+# The Motion Path element
+
+The <mpath> SVG sub-element for the <animateMotion> element provides the ability to reference an external <path> element as the definition of a motion path.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/mpath
+*/
+func Mpath(items ...html.Item) *html.Element { return El("mpath", items...) }
+
+/*
+# The Path element
+
+The <path> SVG element is the generic element to define a shape. All the basic shapes can be created with a path element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/path
+*/
+func Path(items ...html.Item) *html.Element { return El("path", items...) }
+
+/*
+# The Pattern element
+
+The <pattern> SVG element defines a graphics object which can be redrawn at repeated x- and y-coordinate intervals ("tiled") to cover an area.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/pattern
+*/
+func Pattern(items ...html.Item) *html.Element { return El("pattern", items...) }
+
+/*
+# The Polygon element
+
+The <polygon> SVG element defines a closed shape consisting of a set of connected straight line segments. The last point is connected to the first point.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/polygon
+*/
+func Polygon(items ...html.Item) *html.Element { return El("polygon", items...) }
+
+/*
+# The Polyline element
+
+The <polyline> SVG element is an SVG basic shape that creates straight lines connecting several points. Typically a polyline is used to create open shapes as the last point doesn't have to be connected to the first point.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/polyline
+*/
+func Polyline(items ...html.Item) *html.Element { return El("polyline", items...) }
+
+/*
+# The Radial Gradient element
+
+The <radialGradient> SVG element lets authors define radial gradients that can be applied to fill or stroke of graphical elements.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/radialGradient
+*/
+func RadialGradient(items ...html.Item) *html.Element { return El("radialGradient", items...) }
+
+/*
+# The Rectangle element
+
+The <rect> SVG element is a basic SVG shape that draws rectangles, defined by their position, width, and height. The rectangles may have their corners rounded.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/rect
+*/
+func Rect(items ...html.Item) *html.Element { return El("rect", items...) }
+
+/*
+# The Script element
+
+The <script> SVG element allows to add scripts to an SVG document.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/script
+*/
+func Script(items ...html.Item) *html.Element { return El("script", items...) }
+
+/*
+# The Set element
+
+The <set> SVG element provides a method of setting the value of an attribute for a specified duration.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/set
+*/
+func Set(items ...html.Item) *html.Element { return El("set", items...) }
+
+/*
+# The Stop element
+
+The <stop> SVG element defines a color and its position to use on a gradient. This element is always a child of a <linearGradient> or <radialGradient> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/stop
+*/
+func Stop(items ...html.Item) *html.Element { return El("stop", items...) }
+
+/*
+# The Style element
+
+The <style> SVG element allows style sheets to be embedded directly within SVG content.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/style
+*/
+func Style(items ...html.Item) *html.Element { return El("style", items...) }
+
+/*
+# The SVG element
+
+The <svg> SVG element is a container that defines a new coordinate system and viewport. It is used as the outermost element of SVG documents, but it can also be used to embed an SVG fragment inside an SVG or HTML document.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/svg
+*/
+func Svg(items ...html.Item) *html.Element { return El("svg", items...) }
+
+/*
+# The Switch element
+
+The <switch> SVG element evaluates any requiredFeatures, requiredExtensions and systemLanguage attributes on its direct child elements in order, and then renders the first child where these attributes evaluate to true.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/switch
+*/
+func Switch(items ...html.Item) *html.Element { return El("switch", items...) }
+
+/*
+# The Symbol element
+
+The <symbol> SVG element is used to define graphical template objects which can be instantiated by a <use> element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/symbol
+*/
+func Symbol(items ...html.Item) *html.Element { return El("symbol", items...) }
+
+/*
+# The Text element
+
+The <text> SVG element draws a graphics element consisting of text. It's possible to apply a gradient, pattern, clipping path, mask, or filter to <text>, like any other SVG graphics element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/text
+*/
+func Text(items ...html.Item) *html.Element { return El("text", items...) }
+
+/*
+# The Text Path element
+
+The <textPath> SVG element is used to render text along the shape of a <path> element. The text must be enclosed in the <textPath> element and its href attribute is used to reference the desired <path>.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/textPath
+*/
+func TextPath(items ...html.Item) *html.Element { return El("textPath", items...) }
+
+/*
+# The Title element
+
+The <title> SVG element provides an accessible, short-text description of any SVG container element or graphics element.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/title
+*/
+func Title(items ...html.Item) *html.Element { return El("title", items...) }
+
+/*
+# The Text Span element
+
+The <tspan> SVG element defines a subtext within a <text> element or another <tspan> element. It allows for adjustment of the style and/or position of that subtext as needed.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/tspan
+*/
+func Tspan(items ...html.Item) *html.Element { return El("tspan", items...) }
+
+/*
+# The Use element
+
+The <use> element takes nodes from within an SVG document, and duplicates them somewhere else.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/use
+*/
+func Use(items ...html.Item) *html.Element { return El("use", items...) }
+
+/*
+# The View element
+
+The <view> SVG element defines a particular view of an SVG document. A specific view can be displayed by referencing the <view> element's id as the target fragment of a URL.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Element/view
+*/
+func View(items ...html.Item) *html.Element { return El("view", items...) }
+
+// Attributes
+
+func number(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func numbers(ns ...float64) string {
+	values := make([]string, len(ns))
+	for i, n := range ns {
+		values[i] = number(n)
+	}
+	return strings.Join(values, " ")
+}
+
+/*
+# SVG attribute: viewBox
+
+The viewBox attribute defines the position and dimension, in user space, of an SVG viewport.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Attribute/viewBox
+*/
+func ViewBox(minX, minY, width, height float64) *html.Attribute {
+	return html.Attr("viewBox", numbers(minX, minY, width, height))
+}
+
+/*
+# SVG attribute: d
+
+The d attribute defines a path to be drawn.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Attribute/d
+*/
+func D(path string) *html.Attribute { return html.Attr("d", path) }
+
+// Point is a coordinate pair in the user coordinate system.
+type Point struct {
+	X, Y float64
+}
+
+/*
+# SVG attribute: points
+
+The points attribute defines a list of points. Each point is defined by a pair of numbers representing an X and a Y coordinate in the user coordinate system.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Attribute/points
+*/
+func Points(points ...Point) *html.Attribute {
+	values := make([]string, len(points))
+	for i, point := range points {
+		values[i] = number(point.X) + "," + number(point.Y)
+	}
+	return html.Attr("points", strings.Join(values, " "))
+}
+
+// TransformFunc is a single transform function of a transform list, see Transform.
+type TransformFunc string
+
+// Moves the object by x and y.
+func Translate(x, y float64) TransformFunc {
+	return TransformFunc("translate(" + numbers(x, y) + ")")
+}
+
+// Scales the object by x and y.
+func Scale(x, y float64) TransformFunc {
+	return TransformFunc("scale(" + numbers(x, y) + ")")
+}
+
+// Rotates the object by the given degrees about the point (x, y).
+func Rotate(angle, x, y float64) TransformFunc {
+	return TransformFunc("rotate(" + numbers(angle, x, y) + ")")
+}
+
+// Skews the object along the x axis by the given degrees.
+func SkewX(angle float64) TransformFunc {
+	return TransformFunc("skewX(" + number(angle) + ")")
+}
+
+// Skews the object along the y axis by the given degrees.
+func SkewY(angle float64) TransformFunc {
+	return TransformFunc("skewY(" + number(angle) + ")")
+}
+
+// Applies the transformation matrix [a c e] [b d f] [0 0 1].
+func Matrix(a, b, c, d, e, f float64) TransformFunc {
+	return TransformFunc("matrix(" + numbers(a, b, c, d, e, f) + ")")
+}
+
+/*
+# SVG attribute: transform
+
+The transform attribute defines a list of transform definitions that are applied to an element and the element's children.
+
+Usage:
+
+	svg.G(svg.Transform(svg.Translate(12, 12), svg.Rotate(45, 0, 0)))
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Attribute/transform
+*/
+func Transform(funcs ...TransformFunc) *html.Attribute {
+	values := make([]string, len(funcs))
+	for i, fn := range funcs {
+		values[i] = string(fn)
+	}
+	return html.Attr("transform", strings.Join(values, " "))
+}
+
+// Sets the xmlns attribute to the SVG namespace, required for standalone SVG documents.
+func XMLNS() *html.Attribute { return html.Attr("xmlns", Namespace) }
+
+/*
+# SVG attribute: preserveAspectRatio
+
+The preserveAspectRatio attribute indicates how an element with a viewBox providing a given aspect ratio must fit into a viewport with a different aspect ratio.
+
+https://developer.mozilla.org/en-US/docs/Web/SVG/Reference/Attribute/preserveAspectRatio
+*/
+func PreserveAspectRatio(value string) *html.Attribute {
+	return html.Attr("preserveAspectRatio", value)
+}
+
+func Href(href string) *html.Attribute            { return html.Attr("href", href) }
+func Fill(paint string) *html.Attribute           { return html.Attr("fill", paint) }
+func FillOpacity(n float64) *html.Attribute       { return html.Attr("fill-opacity", number(n)) }
+func FillRule(rule string) *html.Attribute        { return html.Attr("fill-rule", rule) }
+func ClipRule(rule string) *html.Attribute        { return html.Attr("clip-rule", rule) }
+func Stroke(paint string) *html.Attribute         { return html.Attr("stroke", paint) }
+func StrokeWidth(n float64) *html.Attribute       { return html.Attr("stroke-width", number(n)) }
+func StrokeOpacity(n float64) *html.Attribute     { return html.Attr("stroke-opacity", number(n)) }
+func StrokeLinecap(value string) *html.Attribute  { return html.Attr("stroke-linecap", value) }
+func StrokeLinejoin(value string) *html.Attribute { return html.Attr("stroke-linejoin", value) }
+func StrokeDasharray(ns ...float64) *html.Attribute {
+	return html.Attr("stroke-dasharray", numbers(ns...))
+}
+func StrokeDashoffset(n float64) *html.Attribute { return html.Attr("stroke-dashoffset", number(n)) }
+func Opacity(n float64) *html.Attribute          { return html.Attr("opacity", number(n)) }
+func Width(n float64) *html.Attribute            { return html.Attr("width", number(n)) }
+func Height(n float64) *html.Attribute           { return html.Attr("height", number(n)) }
+func X(n float64) *html.Attribute                { return html.Attr("x", number(n)) }
+func Y(n float64) *html.Attribute                { return html.Attr("y", number(n)) }
+func X1(n float64) *html.Attribute               { return html.Attr("x1", number(n)) }
+func Y1(n float64) *html.Attribute               { return html.Attr("y1", number(n)) }
+func X2(n float64) *html.Attribute               { return html.Attr("x2", number(n)) }
+func Y2(n float64) *html.Attribute               { return html.Attr("y2", number(n)) }
+func Cx(n float64) *html.Attribute               { return html.Attr("cx", number(n)) }
+func Cy(n float64) *html.Attribute               { return html.Attr("cy", number(n)) }
+func R(n float64) *html.Attribute                { return html.Attr("r", number(n)) }
+func Rx(n float64) *html.Attribute               { return html.Attr("rx", number(n)) }
+func Ry(n float64) *html.Attribute               { return html.Attr("ry", number(n)) }
+func Dx(n float64) *html.Attribute               { return html.Attr("dx", number(n)) }
+func Dy(n float64) *html.Attribute               { return html.Attr("dy", number(n)) }
+func Offset(value string) *html.Attribute        { return html.Attr("offset", value) }
+func StopColor(color string) *html.Attribute     { return html.Attr("stop-color", color) }
+func StopOpacity(n float64) *html.Attribute      { return html.Attr("stop-opacity", number(n)) }
+func GradientUnits(units string) *html.Attribute { return html.Attr("gradientUnits", units) }
+func PatternUnits(units string) *html.Attribute  { return html.Attr("patternUnits", units) }
+func ClipPathAttr(value string) *html.Attribute  { return html.Attr("clip-path", value) }
+func MaskAttr(value string) *html.Attribute      { return html.Attr("mask", value) }
+func FilterAttr(value string) *html.Attribute    { return html.Attr("filter", value) }
+func StdDeviation(n float64) *html.Attribute     { return html.Attr("stdDeviation", number(n)) }
+func TextAnchor(value string) *html.Attribute    { return html.Attr("text-anchor", value) }
+func MarkerEnd(value string) *html.Attribute     { return html.Attr("marker-end", value) }
+func MarkerStart(value string) *html.Attribute   { return html.Attr("marker-start", value) }
+func VectorEffect(value string) *html.Attribute  { return html.Attr("vector-effect", value) }
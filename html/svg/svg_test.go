@@ -0,0 +1,86 @@
+package svg_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/mathml"
+	"github.com/canpacis/pacis/html/svg"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(node html.Node, mode html.RenderMode) string {
+	cw := html.NewChunkWriterWithMode(mode)
+	node.Render(cw)
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		html.Render(chunk, context.Background(), buf)
+	}
+	return buf.String()
+}
+
+func TestSVG(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		Node     html.Node
+		Rendered string
+	}{
+		{
+			svg.Svg(
+				svg.ViewBox(0, 0, 24, 24),
+				svg.Path(svg.D("M5 12h14")),
+			),
+			`<svg viewBox="0 0 24 24"><path d="M5 12h14"/></svg>`,
+		},
+		{
+			svg.Defs(
+				svg.LinearGradient(
+					html.ID("fade"),
+					svg.Stop(svg.Offset("0%"), svg.StopColor("#fff")),
+				),
+			),
+			`<defs><linearGradient id="fade"><stop offset="0%" stop-color="#fff"/></linearGradient></defs>`,
+		},
+		{
+			svg.Polyline(svg.Points(svg.Point{X: 1, Y: 2}, svg.Point{X: 3.5, Y: 4})),
+			`<polyline points="1,2 3.5,4"/>`,
+		},
+		{
+			svg.G(svg.Transform(svg.Translate(12, 12), svg.Rotate(45, 0, 0), svg.Scale(1.5, 1.5))),
+			`<g transform="translate(12 12) rotate(45 0 0) scale(1.5 1.5)"/>`,
+		},
+		{
+			svg.Svg(svg.XMLNS(), svg.Circle(svg.Cx(12), svg.Cy(12), svg.R(10))),
+			`<svg xmlns="http://www.w3.org/2000/svg"><circle cx="12" cy="12" r="10"/></svg>`,
+		},
+		{
+			mathml.Math(mathml.Display("block"), mathml.Mfrac(mathml.Mi(html.Text("a")), mathml.Mspace())),
+			`<math display="block"><mfrac><mi>a</mi><mspace/></mfrac></math>`,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(test.Rendered, render(test.Node, html.ModeCompact))
+	}
+
+	// Attribute values of foreign elements are always quoted, so that self closing tags stay intact
+	minified := render(svg.Path(html.Attr("d", "M0")), html.ModeMinify)
+	assert.Equal(`<path d="M0"/>`, minified)
+}
+
+func TestParseForeign(t *testing.T) {
+	assert := assert.New(t)
+
+	source := `<div><svg viewBox="0 0 10 10"><clipPath id="c"><rect width="10" height="10"/></clipPath></svg><p>text</p></div>`
+	nodes, err := html.Parse(strings.NewReader(source))
+	assert.NoError(err)
+	assert.Equal(source, render(nodes, html.ModeCompact))
+
+	path := svg.Path()
+	assert.Equal(html.NamespaceSVG, path.Namespace())
+	assert.Equal(html.NamespaceHTML, html.Div().Namespace())
+}
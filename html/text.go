@@ -0,0 +1,340 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+RenderText renders the given node as readable plain text, e.g. for the text/plain
+alternative of an email that is built from the same components as a page.
+
+The node is rendered with ctx first, so components, deferred attributes and nodes of
+other packages are resolved like they would be in a response. The resulting tree is
+then written as text:
+
+  - Block elements (p, div, headings, ...) become paragraphs separated by a blank line
+  - <li> elements become bullets, or numbers within an <ol>
+  - <a> elements become "text (url)"
+  - <table> elements become aligned columns
+  - <script>, <style>, <template> and <head> elements are skipped
+
+Usage:
+
+	var buf bytes.Buffer
+	if err := html.RenderText(ctx, html.Component(WelcomeEmail), &buf); err != nil { ... }
+*/
+func RenderText(ctx context.Context, node Node, w io.Writer) error {
+	cw := NewChunkWriter()
+	if err := node.Render(cw); err != nil {
+		return err
+	}
+	markup := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := Render(chunk, ctx, markup); err != nil {
+			return err
+		}
+	}
+
+	nodes, err := Parse(markup)
+	if err != nil {
+		return err
+	}
+
+	t := &textwriter{}
+	t.nodes(nodes)
+	text := strings.TrimSpace(t.buf.String())
+	if len(text) > 0 {
+		text += "\n"
+	}
+	_, err = io.WriteString(w, text)
+	return err
+}
+
+// Elements whose content is not part of the readable text
+var skippedelements = []string{"head", "script", "style", "template", "noscript", "svg", "math", "!DOCTYPE"}
+
+// Elements that are written as separate paragraphs
+var blockelements = []string{
+	"address", "article", "aside", "body", "details", "dialog", "div", "dl", "fieldset", "figcaption", "figure", "footer",
+	"form", "h3", "h4", "h5", "h6", "header", "hgroup", "html", "main", "nav", "p", "search", "section", "summary",
+}
+
+// textwriter builds the plain text output, whitespace is collapsed and
+// line breaks between blocks are only written once the next content arrives.
+type textwriter struct {
+	buf strings.Builder
+	// Number of line breaks to write before the next content
+	breaks int
+	// Whether a space should be written before the next word
+	space bool
+	// Compact writers separate blocks with a single line break, used for list items and table cells
+	compact bool
+	// Position of the next item of the enclosing <ol>, zero within an <ul>
+	counter int
+}
+
+// block requests n line breaks before the next content.
+func (t *textwriter) block(n int) {
+	if t.compact {
+		n = min(n, 1)
+	}
+	t.breaks = max(t.breaks, n)
+	t.space = false
+}
+
+func (t *textwriter) flush() {
+	if t.buf.Len() > 0 {
+		t.buf.WriteString(strings.Repeat("\n", t.breaks))
+	}
+	t.breaks = 0
+}
+
+// inline writes text with collapsed whitespace.
+func (t *textwriter) inline(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if len(s) > 0 {
+			t.space = true
+		}
+		return
+	}
+	if strings.IndexAny(s[:1], " \t\n\r\f") == 0 {
+		t.space = true
+	}
+	if t.breaks > 0 {
+		t.flush()
+	} else if t.space && t.buf.Len() > 0 {
+		t.buf.WriteByte(' ')
+	}
+	t.buf.WriteString(strings.Join(words, " "))
+	t.space = strings.IndexAny(s[len(s)-1:], " \t\n\r\f") == 0
+}
+
+// raw writes the given text as is.
+func (t *textwriter) raw(s string) {
+	if len(s) == 0 {
+		return
+	}
+	t.flush()
+	t.buf.WriteString(s)
+	t.space = false
+}
+
+func (t *textwriter) nodes(nodes []Node) {
+	for _, node := range nodes {
+		t.node(node)
+	}
+}
+
+func (t *textwriter) node(node Node) {
+	switch node := node.(type) {
+	case Text:
+		t.inline(string(node))
+	case Frag:
+		t.nodes(node)
+	case *Element:
+		t.element(node)
+	}
+}
+
+// sub renders the given nodes with a new compact writer and returns the text.
+func (t *textwriter) sub(nodes []Node) string {
+	inner := &textwriter{compact: true}
+	inner.nodes(nodes)
+	return strings.TrimSpace(inner.buf.String())
+}
+
+func (t *textwriter) element(el *Element) {
+	tag := el.Tag()
+	if oneof(tag, skippedelements...) || el.HasAttribute("hidden") {
+		return
+	}
+
+	switch tag {
+	case "br":
+		t.raw("\n")
+	case "hr":
+		t.block(2)
+		t.raw("---")
+		t.block(2)
+	case "img":
+		t.inline(el.GetAttribute("alt"))
+	case "a":
+		label := t.sub(el.GetNodes())
+		href := el.GetAttribute("href")
+		text := strings.ReplaceAll(label, "\n", " ")
+		if len(href) > 0 && href != label && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "javascript:") {
+			text = strings.TrimSpace(text + " (" + href + ")")
+		}
+		t.inline(text)
+	case "pre":
+		t.block(2)
+		t.raw(strings.TrimSuffix(strings.TrimPrefix(textcontent(el), "\n"), "\n"))
+		t.block(2)
+	case "h1", "h2":
+		t.block(2)
+		heading := strings.ReplaceAll(t.sub(el.GetNodes()), "\n", " ")
+		underline := "="
+		if tag == "h2" {
+			underline = "-"
+		}
+		t.raw(heading + "\n" + strings.Repeat(underline, utf8.RuneCountInString(heading)))
+		t.block(2)
+	case "ul", "ol", "menu":
+		counter := t.counter
+		t.counter = 0
+		if tag == "ol" {
+			t.counter = 1
+			if start, err := strconv.Atoi(el.GetAttribute("start")); err == nil {
+				t.counter = start
+			}
+		}
+		t.block(2)
+		t.nodes(el.GetNodes())
+		t.block(2)
+		t.counter = counter
+	case "li":
+		bullet := "- "
+		if t.counter > 0 {
+			bullet = strconv.Itoa(t.counter) + ". "
+			t.counter++
+		}
+		t.block(1)
+		t.raw(indent(bullet, t.sub(el.GetNodes())))
+		t.block(1)
+	case "dt", "dd":
+		t.block(1)
+		text := t.sub(el.GetNodes())
+		if tag == "dd" {
+			text = indent("  ", text)
+		}
+		t.raw(text)
+		t.block(1)
+	case "blockquote":
+		t.block(2)
+		inner := &textwriter{}
+		inner.nodes(el.GetNodes())
+		lines := strings.Split(strings.TrimSpace(inner.buf.String()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		t.raw(strings.Join(lines, "\n"))
+		t.block(2)
+	case "table":
+		t.block(2)
+		t.raw(t.table(el))
+		t.block(2)
+	default:
+		if oneof(tag, blockelements...) {
+			t.block(2)
+			t.nodes(el.GetNodes())
+			t.block(2)
+		} else {
+			t.nodes(el.GetNodes())
+		}
+	}
+}
+
+// table writes the rows of a table as columns aligned with spaces, a header row is underlined.
+func (t *textwriter) table(table *Element) string {
+	type row struct {
+		cells  []string
+		header bool
+	}
+	rows := []row{}
+	captions := []string{}
+
+	var collect func(nodes []Node, header bool)
+	collect = func(nodes []Node, header bool) {
+		for _, node := range childelements(nodes) {
+			switch node.Tag() {
+			case "caption":
+				captions = append(captions, t.sub(node.GetNodes()))
+			case "thead":
+				collect(node.GetNodes(), true)
+			case "tbody", "tfoot":
+				collect(node.GetNodes(), false)
+			case "tr":
+				r := row{header: header}
+				allth := true
+				for _, cell := range childelements(node.GetNodes()) {
+					if cell.Tag() != "td" && cell.Tag() != "th" {
+						continue
+					}
+					allth = allth && cell.Tag() == "th"
+					r.cells = append(r.cells, strings.Join(strings.Fields(t.sub(cell.GetNodes())), " "))
+				}
+				r.header = r.header || (allth && len(r.cells) > 0 && len(rows) == 0)
+				rows = append(rows, r)
+			}
+		}
+	}
+	collect(table.GetNodes(), false)
+
+	widths := []int{}
+	for _, r := range rows {
+		for i, cell := range r.cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	line := func(cells []string) string {
+		b := new(strings.Builder)
+		for i, cell := range cells {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+
+	lines := captions
+	for i, r := range rows {
+		lines = append(lines, line(r.cells))
+		if r.header && (i+1 >= len(rows) || !rows[i+1].header) {
+			rule := make([]string, len(widths))
+			for j, width := range widths {
+				rule[j] = strings.Repeat("-", width)
+			}
+			lines = append(lines, line(rule))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent prefixes the first line of text with prefix and the following lines with spaces of the same width.
+func indent(prefix, text string) string {
+	lines := strings.Split(text, "\n")
+	pad := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = prefix + line
+		case len(line) > 0:
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textcontent returns the text of the element and its descendants as is.
+func textcontent(el *Element) string {
+	b := new(strings.Builder)
+	Walk(el, func(node Node) error {
+		if text, ok := node.(Text); ok {
+			b.WriteString(string(text))
+		}
+		return nil
+	})
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+type namekey struct{}
+
+func TestRenderText(t *testing.T) {
+	assert := assert.New(t)
+
+	greeting := html.Component(func(ctx context.Context) html.Node {
+		return html.P(html.Text("Hi "), html.Text(ctx.Value(namekey{}).(string)), html.Text(","))
+	})
+
+	email := html.Html(
+		html.Head(html.Title(html.Text("Welcome")), html.Style(html.RawUnsafe("p { color: red; }"))),
+		html.Body(
+			html.H1(html.Text("Welcome aboard")),
+			greeting,
+			html.P(
+				html.Text("Your   account is ready, "),
+				html.A(html.Href("https://example.com/login"), html.Text("sign in")),
+				html.Text(" to continue."),
+			),
+			html.Ul(
+				html.Li(html.Text("Invite your team")),
+				html.Li(html.Text("Set up billing")),
+			),
+			html.Ol(
+				html.Li(html.Text("First")),
+				html.Li(html.Text("Second")),
+			),
+			html.Table(
+				html.Thead(html.Tr(html.Th(html.Text("Plan")), html.Th(html.Text("Price")))),
+				html.Tbody(
+					html.Tr(html.Td(html.Text("Starter")), html.Td(html.Text("$9"))),
+					html.Tr(html.Td(html.Text("Pro")), html.Td(html.Text("$29"))),
+				),
+			),
+			html.Script(html.RawUnsafe("alert('hi')")),
+			html.Template(html.P(html.Text("hidden"))),
+			html.Div(html.Text("Thanks,"), html.Br(), html.Text("The team")),
+		),
+	)
+
+	ctx := context.WithValue(context.Background(), namekey{}, "Jane")
+	buf := new(bytes.Buffer)
+	assert.NoError(html.RenderText(ctx, email, buf))
+	assert.Equal(`Welcome aboard
+==============
+
+Hi Jane,
+
+Your account is ready, sign in (https://example.com/login) to continue.
+
+- Invite your team
+- Set up billing
+
+1. First
+2. Second
+
+Plan     Price
+-------  -----
+Starter  $9
+Pro      $29
+
+Thanks,
+The team
+`, buf.String())
+}
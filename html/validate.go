@@ -0,0 +1,239 @@
+package html
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Violation is a child element that the content model of its ancestor does not allow.
+type Violation struct {
+	// Path of the element from the root, e.g. "main > p.lead > div"
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ContentModelError is returned by Validate with the violations of a node tree.
+type ContentModelError struct {
+	Violations []Violation
+}
+
+func (e *ContentModelError) Error() string {
+	b := new(strings.Builder)
+	b.WriteString("invalid content model:")
+	for _, violation := range e.Violations {
+		b.WriteString("\n\t" + violation.String())
+	}
+	return b.String()
+}
+
+// phrasing is the set of phrasing content elements, custom elements are phrasing content as well,
+// see https://html.spec.whatwg.org/multipage/dom.html#phrasing-content
+var phrasing = set("a abbr area audio b bdi bdo br button canvas cite code data datalist del dfn em embed i iframe img input ins kbd label link map mark math meta meter noscript object output picture progress q ruby s samp script select slot small span strong sub sup svg template textarea time u var video wbr")
+
+// phrasingonly is the set of elements that only allow phrasing content.
+var phrasingonly = set("abbr b bdi bdo button cite code data dfn em h1 h2 h3 h4 h5 h6 i kbd label legend mark output p pre q s samp small span strong sub summary sup time u var")
+
+// transparent is the set of elements whose content model is the content model of their parent.
+var transparent = set("a audio canvas del ins map noscript object slot video")
+
+// parents are the elements that are only allowed as children of the given elements.
+var parents = map[string][]string{
+	"li":       {"ul", "ol", "menu"},
+	"dt":       {"dl", "div"},
+	"dd":       {"dl", "div"},
+	"tr":       {"table", "thead", "tbody", "tfoot"},
+	"td":       {"tr"},
+	"th":       {"tr"},
+	"thead":    {"table"},
+	"tbody":    {"table"},
+	"tfoot":    {"table"},
+	"caption":  {"table"},
+	"colgroup": {"table"},
+	"option":   {"select", "datalist", "optgroup"},
+	"optgroup": {"select"},
+}
+
+func set(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, name := range strings.Fields(s) {
+		m[name] = true
+	}
+	return m
+}
+
+// interactive reports whether the element is interactive content,
+// see https://html.spec.whatwg.org/multipage/dom.html#interactive-content
+func interactive(el *Element) bool {
+	switch el.Tag() {
+	case "a", "button", "details", "embed", "iframe", "label", "select", "textarea":
+		return true
+	case "input":
+		return !strings.EqualFold(el.GetAttribute("type"), "hidden")
+	case "audio", "video":
+		return el.HasAttribute("controls")
+	case "img":
+		return el.HasAttribute("usemap")
+	}
+	return el.HasAttribute("tabindex")
+}
+
+// contentmodel is the state of the validation at an element.
+type contentmodel struct {
+	// Closest ancestor that only allows phrasing content
+	phrasing string
+	// Closest <a> or <button> ancestor, they do not allow interactive content
+	interactive string
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) report(path, message string) {
+	v.violations = append(v.violations, Violation{Path: path, Message: message})
+}
+
+/*
+Validate checks the elements of the node tree against the content models of their ancestors
+and returns a *ContentModelError with the violations. Browsers silently repair such markup
+while parsing (e.g. a <div> closes an open <p>), which moves the elements around and breaks
+the selectors that target them.
+
+The following are reported:
+  - Flow content (e.g. <div>, <ul>) within elements that only allow phrasing content (e.g. <p>, <span>, <h1>)
+  - List items, table parts and options outside of their parents (e.g. an <li> that is not a child of <ul>)
+  - Interactive content (e.g. <button>, <input>, <a>) within <a> and <button> elements
+
+Components are NOT rendered, so the nodes they return are not validated: only the static
+parts of the tree are checked. The server validates the pages when they are built with
+the Validate option.
+
+Usage:
+
+	if err := html.Validate(html.P(html.Div())); err != nil {
+		// invalid content model:
+		//	p > div: <div> is not allowed in <p>, it only allows phrasing content
+	}
+*/
+func Validate(node Node) error {
+	v := &validator{}
+	v.walk([]Node{node}, nil, "", contentmodel{})
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ContentModelError{Violations: v.violations}
+}
+
+// walk validates the element children of the nodes, parent is their parent element or nil.
+func (v *validator) walk(nodes []Node, parent *Element, path string, model contentmodel) {
+	children := v.children(nodes, []*Element{})
+	for _, el := range children {
+		child := PathSegment(el, children)
+		if len(path) > 0 {
+			child = path + " > " + child
+		}
+		if el.ns != NamespaceHTML || strings.HasPrefix(el.name, "!") {
+			// Foreign content of <svg> and <math> elements is not validated
+			continue
+		}
+		v.element(el, parent, child, model)
+		if el.Tag() == "template" {
+			// The contents of templates are inserted next to them, e.g. by x-for
+			v.walk(el.nodes, parent, child, model)
+			continue
+		}
+		v.walk(el.nodes, el, child, v.model(el, model))
+	}
+}
+
+// children appends the element children among the nodes, flattening fragments and error
+// boundaries. The nodes of Once and HeadPortal might be moved to another element, they are
+// validated on their own.
+func (v *validator) children(nodes []Node, children []*Element) []*Element {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *Element:
+			children = append(children, node)
+		case Frag:
+			children = v.children(node, children)
+		case *ErrorBoundaryNode:
+			children = v.children([]Node{node.node}, children)
+		case *OnceNode:
+			v.walk([]Node{node.node}, nil, "", contentmodel{})
+		case *HeadPortalNode:
+			v.walk([]Node{node.node}, nil, "head", contentmodel{})
+		}
+	}
+	return children
+}
+
+func (v *validator) element(el, parent *Element, path string, model contentmodel) {
+	name := el.Tag()
+	if len(model.phrasing) > 0 && !phrasing[name] && !strings.Contains(name, "-") {
+		v.report(path, "<"+name+"> is not allowed in <"+model.phrasing+">, it only allows phrasing content")
+	}
+	if allowed, ok := parents[name]; ok && parent != nil {
+		found := false
+		for _, p := range allowed {
+			if parent.Tag() == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(path, "<"+name+"> must be a child of <"+strings.Join(allowed, ">, <")+">, not <"+parent.Tag()+">")
+		}
+	}
+	if len(model.interactive) > 0 && interactive(el) {
+		if name == model.interactive {
+			v.report(path, "<"+name+"> is not allowed in another <"+name+">")
+		} else {
+			v.report(path, "interactive <"+name+"> is not allowed in <"+model.interactive+">")
+		}
+	}
+}
+
+// model returns the content model of the children of the element.
+func (v *validator) model(el *Element, model contentmodel) contentmodel {
+	name := el.Tag()
+	switch {
+	case phrasingonly[name]:
+		model.phrasing = name
+	case !transparent[name]:
+		model.phrasing = ""
+	}
+	if name == "a" || name == "button" {
+		model.interactive = name
+	}
+	return model
+}
+
+// PathSegment describes the element within its siblings, e.g. "a#home", "li:nth-of-type(2)"
+// or "p.lead". Paths of elements join the segments of their ancestors with " > ", see
+// ContentModelError.
+func PathSegment(el *Element, siblings []*Element) string {
+	if id := el.GetAttribute("id"); len(id) > 0 {
+		return el.Tag() + "#" + id
+	}
+	s := el.Tag()
+	if class := strings.Fields(el.GetAttribute("class")); len(class) > 0 {
+		s += "." + class[0]
+	}
+	index, count := 0, 0
+	for _, sibling := range siblings {
+		if sibling.Tag() == el.Tag() {
+			count++
+			if sibling == el {
+				index = count
+			}
+		}
+	}
+	if count > 1 {
+		s += ":nth-of-type(" + strconv.Itoa(index) + ")"
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package html_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(html.Validate(tree()))
+	assert.NoError(html.Validate(html.Fragment(
+		html.P(html.A(html.Href("/"), html.Strong(html.Text("Home")))),
+		html.Div(html.A(html.Href("/"), html.Div(html.Text("Card")))),
+		html.Dl(html.Div(html.Dt(), html.Dd())),
+		html.Ul(html.Template(html.Li())),
+		html.Span(html.El("x-icon"), html.El("svg", html.El("g"))),
+		html.Button(html.Input(html.Type("hidden"))),
+	)))
+
+	err := html.Validate(html.Main(
+		html.P(html.Class("lead"), html.Div()),
+		html.Li(),
+		html.P(html.A(html.Href("/"), html.Div())),
+		html.ErrorBoundary(html.Button(html.Button()), func(error) html.Node { return nil }),
+		html.A(html.Href("/"), html.Input(html.Type("email")), html.Span(html.Attr("tabindex", "0"))),
+	))
+	var cmerr *html.ContentModelError
+	assert.True(errors.As(err, &cmerr))
+	assert.Equal([]html.Violation{
+		{Path: "main > p.lead:nth-of-type(1) > div", Message: "<div> is not allowed in <p>, it only allows phrasing content"},
+		{Path: "main > li", Message: "<li> must be a child of <ul>, <ol>, <menu>, not <main>"},
+		{Path: "main > p:nth-of-type(2) > a > div", Message: "<div> is not allowed in <p>, it only allows phrasing content"},
+		{Path: "main > button > button", Message: "<button> is not allowed in another <button>"},
+		{Path: "main > a > input", Message: "interactive <input> is not allowed in <a>"},
+		{Path: "main > a > span", Message: "interactive <span> is not allowed in <a>"},
+	}, cmerr.Violations)
+	assert.Equal("invalid content model:\n\tmain > li: <li> must be a child of <ul>, <ol>, <menu>, not <main>", (&html.ContentModelError{Violations: cmerr.Violations[1:2]}).Error())
+}
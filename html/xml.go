@@ -0,0 +1,172 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+RenderXML renders the given node as an XML document, e.g. a feed, a sitemap or an
+SVG file, with the XML render mode:
+
+  - Tag and attribute names are written as they are given, there is no lowercasing
+  - Elements without children self close (<x/>), there is no list of void elements
+  - Text and attribute values are escaped by XML rules, attributes are always quoted
+    and attributes with empty values are written as key=""
+
+The declaration is not added automatically, place XMLDeclaration at the start of the
+document. Namespaces are declared with XMLNS and prefixed names are used as they are.
+
+Usage:
+
+	doc := html.Fragment(
+		html.XMLDeclaration,
+		html.El("urlset",
+			html.XMLNS("", "http://www.sitemaps.org/schemas/sitemap/0.9"),
+			html.XMLNS("xhtml", "http://www.w3.org/1999/xhtml"),
+			html.El("url",
+				html.El("loc", html.Text("https://example.com/")),
+				html.El("xhtml:link", html.Attr("rel", "alternate"), html.Attr("hreflang", "de"), html.Attr("href", "https://example.com/de")),
+			),
+		),
+	)
+	if err := html.RenderXML(ctx, doc, w); err != nil { ... }
+*/
+func RenderXML(ctx context.Context, node Node, w io.Writer) error {
+	cw := NewChunkWriterWithMode(ModeXML)
+	if err := node.Render(cw); err != nil {
+		return err
+	}
+	for _, chunk := range cw.Chunks() {
+		if err := Render(chunk, ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderxml renders the element in XML mode.
+func (e *Element) renderxml(w ChunkWriter) error {
+	writestatic(w, "<", e.name)
+
+	for _, prop := range e.properties {
+		if attr, ok := prop.(*DeferredAttribute); ok {
+			w.Write(attr.chunk(ModeXML))
+			continue
+		}
+		applier, ok := prop.(interface {
+			Apply(context.Context, io.Writer) error
+		})
+		if !ok {
+			return fmt.Errorf("property with deferred life cycle (%T) is not implementing the applier interface correctly, add a Apply(context.Context, io.Writer) error method", prop)
+		}
+		w.Write(DynamicChunk(applier.Apply))
+	}
+	for i := range e.attributelist {
+		writeattr(w, &e.attributelist[i], ModeXML)
+	}
+
+	if len(e.nodes) == 0 {
+		writestatic(w, "/>")
+		return nil
+	}
+
+	writestatic(w, ">")
+	for _, node := range e.nodes {
+		if err := node.Render(w); err != nil {
+			return err
+		}
+	}
+	writestatic(w, "</", e.name, ">")
+	return nil
+}
+
+// XMLDeclaration declares the version and the encoding of an XML document, see RenderXML.
+var XMLDeclaration = ProcessingInstruction("xml", `version="1.0" encoding="UTF-8"`)
+
+// ProcessingInstruction creates a node that renders an XML processing instruction,
+// e.g. <?xml-stylesheet type="text/xsl" href="/feed.xsl"?>. The data is written as is.
+func ProcessingInstruction(target, data string) Node {
+	if len(data) == 0 {
+		return RawUnsafe("<?" + target + "?>")
+	}
+	return RawUnsafe("<?" + target + " " + data + "?>")
+}
+
+// CDATA creates a node that renders the given text in a CDATA section, the text is
+// not escaped. Occurrences of "]]>" are split across two sections.
+func CDATA(text string) Node {
+	return RawUnsafe("<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>")
+}
+
+// XMLNS declares an XML namespace on an element with the given prefix, the default
+// namespace of the element is declared if the prefix is empty.
+func XMLNS(prefix, uri string) *Attribute {
+	if len(prefix) == 0 {
+		return Attr("xmlns", uri)
+	}
+	return Attr("xmlns:"+prefix, uri)
+}
+
+// xmlchar reports whether the rune is allowed in an XML 1.0 document.
+func xmlchar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// escapexml escapes the given text by XML rules. Within attribute values the quotes and
+// the whitespace characters that would be normalized by parsers are escaped as well.
+// Characters that are not allowed in XML are replaced with U+FFFD.
+func escapexml(s string, attr bool) string {
+	var b strings.Builder
+	last := 0
+	for i, r := range s {
+		var esc string
+		switch {
+		case r == '&':
+			esc = "&amp;"
+		case r == '<':
+			esc = "&lt;"
+		case r == '>':
+			esc = "&gt;"
+		case attr && r == '"':
+			esc = "&quot;"
+		case attr && r == '\'':
+			esc = "&apos;"
+		case attr && r == '\t':
+			esc = "&#x9;"
+		case attr && r == '\n':
+			esc = "&#xA;"
+		case r == '\r':
+			esc = "&#xD;"
+		case !xmlchar(r):
+			esc = "\uFFFD"
+		case r == utf8.RuneError:
+			// Invalid UTF-8 sequences are replaced, the encoded replacement character is kept
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				esc = "\uFFFD"
+			} else {
+				continue
+			}
+		default:
+			continue
+		}
+		if b.Len() == 0 {
+			b.Grow(len(s) + 8)
+		}
+		b.WriteString(s[last:i])
+		b.WriteString(esc)
+		_, size := utf8.DecodeRuneInString(s[i:])
+		last = i + size
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXML(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := html.Fragment(
+		html.XMLDeclaration,
+		html.El("urlset",
+			html.XMLNS("", "http://www.sitemaps.org/schemas/sitemap/0.9"),
+			html.XMLNS("xhtml", "http://www.w3.org/1999/xhtml"),
+			html.El("url",
+				html.El("loc", html.Text("https://example.com/?a=1&b=<2>")),
+				html.El("xhtml:link", html.Attr("hrefLang", `"de"`), html.Attr("href", "javascript:x")),
+				html.El("br"),
+				html.El("note", html.Attr("hidden", ""), html.CDATA("a ]]> b")),
+			),
+		),
+	)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(html.RenderXML(context.Background(), doc, buf))
+	assert.Equal(
+		`<?xml version="1.0" encoding="UTF-8"?>`+
+			`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">`+
+			`<url>`+
+			`<loc>https://example.com/?a=1&amp;b=&lt;2&gt;</loc>`+
+			`<xhtml:link hrefLang="&quot;de&quot;" href="javascript:x"/>`+
+			`<br/>`+
+			`<note hidden=""><![CDATA[a ]]]]><![CDATA[> b]]></note>`+
+			`</url>`+
+			`</urlset>`,
+		buf.String(),
+	)
+
+	// The output is well formed
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			assert.Equal("EOF", err.Error())
+			break
+		}
+	}
+
+	assert.Equal("<v a=\"&apos;&#xA;\">� &amp;</v>", renderMode(html.El("v", html.Attr("a", "'\n"), html.Text("\x00 &")), html.ModeXML))
+}
+
+func TestXMLDeferredAttribute(t *testing.T) {
+	assert := assert.New(t)
+
+	value := func(value string) func(context.Context) string {
+		return func(context.Context) string { return value }
+	}
+	doc := html.El("entry",
+		html.DeferredAttr("title", value(`"a" & 'b'`)),
+		html.DeferredAttr("hidden", value("")),
+	)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(html.RenderXML(context.Background(), doc, buf))
+	assert.Equal(`<entry title="&quot;a&quot; &amp; &apos;b&apos;" hidden=""/>`, buf.String())
+	assert.NoError(xml.Unmarshal(buf.Bytes(), new(struct{})))
+
+	// HTML writes empty values as boolean attributes
+	assert.Equal(`<div hidden></div>`, renderMode(html.Div(html.DeferredAttr("hidden", value(""))), html.ModeCompact))
+}
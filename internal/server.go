@@ -10,6 +10,8 @@ import (
 type AsyncChunk struct {
 	ID        string
 	Component html.Component
+	// The context the chunk was created in, it carries the values provided by the ancestors of the chunk
+	Context context.Context
 }
 
 type RedirectMark struct {
@@ -26,12 +28,40 @@ type Context struct {
 	AsyncChunks  []AsyncChunk
 	RedirectMark *RedirectMark
 	NotFoundMark bool
+	// Whether async components are rendered in place instead of being streamed, e.g. on static exports
+	InlineAsync bool
+}
+
+type contextkey struct{}
+
+// Value returns the context itself for the internal key, so that it can be found with ContextOf
+// even when it is wrapped by other contexts (e.g. by html.Provide).
+func (c *Context) Value(key any) any {
+	if _, ok := key.(contextkey); ok {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// ContextOf returns the server rendering context that ctx is, or is derived from.
+func ContextOf(ctx context.Context) (*Context, bool) {
+	context, ok := ctx.Value(contextkey{}).(*Context)
+	return context, ok
+}
+
+type inlineasynckey struct{}
+
+// WithInlineAsync returns a copy of ctx for requests whose async components are rendered in place.
+func WithInlineAsync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, inlineasynckey{}, true)
 }
 
 func NewContext(w http.ResponseWriter, r *http.Request) *Context {
+	inline, _ := r.Context().Value(inlineasynckey{}).(bool)
 	return &Context{
 		Context:        r.Context(),
 		ResponseWriter: w,
 		Request:        r,
+		InlineAsync:    inline,
 	}
 }
@@ -2,9 +2,8 @@
 package lucide
 
 import (
-	"strconv"
-
 	html "github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/svg"
 )
 
 func join(props []html.Item, rest ...html.Item) []html.Item {
@@ -12,15 +11,15 @@ func join(props []html.Item, rest ...html.Item) []html.Item {
 }
 
 func Fill(fill string) *html.Attribute {
-	return html.Attr("fill", fill)
+	return svg.Fill(fill)
 }
 
 func Stroke(fill string) *html.Attribute {
-	return html.Attr("stroke", fill)
+	return svg.Stroke(fill)
 }
 
 func StrokeWidth(n float64) *html.Attribute {
-	return html.Attr("stroke-width", strconv.FormatFloat(float64(n), 'f', -1, 64))
+	return svg.StrokeWidth(n)
 }
 
 func Icon(items ...html.Item) *html.Element {
@@ -28,11 +27,11 @@ func Icon(items ...html.Item) *html.Element {
 		Fill("none"),
 		Stroke("currentColor"),
 		StrokeWidth(2),
-		html.Width("24"),
-		html.Height("24"),
-		html.Attr("viewBox", "0 0 24 24"),
-		html.Attr("stroke-linecap", "round"),
-		html.Attr("stroke-linejoin", "round"),
+		svg.Width(24),
+		svg.Height(24),
+		svg.ViewBox(0, 0, 24, 24),
+		svg.StrokeLinecap("round"),
+		svg.StrokeLinejoin("round"),
 	)
-	return html.El("svg", items...)
+	return svg.Svg(items...)
 }
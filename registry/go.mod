@@ -11,4 +11,7 @@ require (
 	github.com/canpacis/pacis v0.3.0
 )
 
-require github.com/Oudwins/tailwind-merge-go v0.2.1 // indirect
+require (
+	github.com/Oudwins/tailwind-merge-go v0.2.1 // indirect
+	golang.org/x/net v0.38.0 // indirect
+)
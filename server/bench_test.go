@@ -0,0 +1,40 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/server"
+)
+
+// Serving a page with a component through the page handler and the default middlewares.
+func BenchmarkPageHandler(b *testing.B) {
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	page := server.PageFunc(func() html.Node {
+		return html.Main(
+			html.H1(html.Text("Benchmark")),
+			html.Component(func(ctx context.Context) html.Node {
+				return html.Ul(html.Map(make([]int, 20), func(i int) html.Node {
+					return html.Li(html.Class("item"), html.Text(strconv.Itoa(i)))
+				}))
+			}),
+		)
+	})
+	handler := server.PageHandler(srv, page, server.DefaultLayout)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/canpacis/pacis/internal"
+)
+
+type exportpage struct {
+	pattern string
+	page    Page
+}
+
+var wildcard = regexp.MustCompile(`\{([^}]*)\}`)
+
+// expand replaces the wildcards of the pattern with the given parameters.
+func expand(pattern string, params map[string]string) (string, error) {
+	var err error
+	expanded := wildcard.ReplaceAllStringFunc(pattern, func(match string) string {
+		name := match[1 : len(match)-1]
+		if name == "$" {
+			return ""
+		}
+		rest := strings.HasSuffix(name, "...")
+		name = strings.TrimSuffix(name, "...")
+		value, ok := params[name]
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("missing path parameter %q for pattern %q", name, pattern))
+			return ""
+		}
+		if !rest {
+			return url.PathEscape(value)
+		}
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return strings.Join(segments, "/")
+	})
+	return expanded, err
+}
+
+// route returns the path part of the pattern of the page, patterns may start with a host.
+func (p *exportpage) route() string {
+	if i := strings.Index(p.pattern, "/"); i > 0 {
+		return p.pattern[i:]
+	}
+	return p.pattern
+}
+
+// expand returns the path of the page with the given path parameters.
+func (p *exportpage) expand(params map[string]string) (string, error) {
+	return expand(p.route(), params)
+}
+
+// paths returns the request paths of a registered page, it returns nil for pages with path
+// parameters and without a Paths method.
+func (p *exportpage) paths() ([]string, error) {
+	if !wildcard.MatchString(strings.ReplaceAll(p.route(), "{$}", "")) {
+		expanded, err := p.expand(nil)
+		return []string{expanded}, err
+	}
+
+	pather, ok := p.page.(interface{ Paths() []map[string]string })
+	if !ok {
+		return nil, nil
+	}
+	paths := []string{}
+	for _, params := range pather.Paths() {
+		expanded, err := p.expand(params)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, expanded)
+	}
+	return paths, nil
+}
+
+// render serves a request to the given path with the handler, async components are rendered in place.
+func (s *Server) render(handler http.Handler, path string) (*httptest.ResponseRecorder, error) {
+	r, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	r = r.WithContext(internal.WithInlineAsync(r.Context()))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w, nil
+}
+
+func writefile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+/*
+Export renders every page registered with HandlePage into an index.html file in dir, so the
+app can be served by any static file server. The files of the build directory, set with
+SetBuildDir, are copied as they are and the not found page is written as 404.html.
+
+Pages with path parameters (e.g. "/blog/{slug}") are exported once for every parameter set
+returned by their optional `Paths() []map[string]string` method and skipped if they do not
+have one. Async components are rendered in place instead of being streamed.
+
+Usage:
+
+	type Post struct{}
+
+	func (*Post) Paths() []map[string]string {
+		return []map[string]string{{"slug": "hello-world"}, {"slug": "release-notes"}}
+	}
+
+	srv.HandlePage("/blog/{slug}", &Post{}, Layout)
+	if err := srv.Export("dist"); err != nil { ... }
+
+Apps that also serve the pages decide when to export them, e.g. with ExportCommand.
+*/
+func (s *Server) Export(dir string) error {
+	logger := s.options.Logger
+	if s.options.Env == Dev {
+		logger.Warn("Exporting pages in the development environment, the pages will load their assets from the dev server")
+	}
+
+	if s.static != nil {
+		err := fs.WalkDir(s.static, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if name == ".vite" {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			data, err := fs.ReadFile(s.static, name)
+			if err != nil {
+				return err
+			}
+			return writefile(filepath.Join(dir, filepath.FromSlash(name)), data)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy the build directory: %w", err)
+		}
+	}
+
+	for _, page := range s.pages {
+		paths, err := page.paths()
+		if err != nil {
+			return err
+		}
+		if paths == nil {
+			logger.Warn("Skipping page with path parameters, add a `Paths() []map[string]string` method to export it", "pattern", page.pattern)
+			continue
+		}
+
+		for _, p := range paths {
+			w, err := s.render(s, p)
+			if err != nil {
+				return fmt.Errorf("failed to export %s: %w", p, err)
+			}
+			if w.Code != http.StatusOK {
+				return fmt.Errorf("failed to export %s: page responded with status %d", p, w.Code)
+			}
+			name := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+p)), "index.html")
+			if err := writefile(name, w.Body.Bytes()); err != nil {
+				return fmt.Errorf("failed to export %s: %w", p, err)
+			}
+			logger.Info("Exported page", "path", p, "file", name)
+		}
+	}
+
+	w, err := s.render(s.notfound, "/404.html")
+	if err != nil {
+		return fmt.Errorf("failed to export the not found page: %w", err)
+	}
+	if err := writefile(filepath.Join(dir, "404.html"), w.Body.Bytes()); err != nil {
+		return fmt.Errorf("failed to export the not found page: %w", err)
+	}
+	return nil
+}
+
+/*
+ExportCommand runs the export command with the given command line arguments, it parses the
+-out flag for the directory the pages are exported to (defaults to "dist") and calls Export.
+The app decides when to run it, Serve never does.
+
+Usage:
+
+	// go run . export -out public
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := srv.ExportCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	srv.Serve()
+*/
+func (s *Server) ExportCommand(args []string) error {
+	set := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := set.String("out", "dist", "the directory the pages are exported to")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	if set.NArg() > 0 {
+		return fmt.Errorf("export: unexpected arguments %q", set.Args())
+	}
+	if err := s.Export(*out); err != nil {
+		return err
+	}
+	s.options.Logger.Info("Exported pages", "dir", *out)
+	return nil
+}
@@ -0,0 +1,96 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/internal"
+	"github.com/canpacis/pacis/server"
+	"github.com/canpacis/pacis/server/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+type post struct{}
+
+func (*post) Page() html.Node {
+	return html.Main(html.Component(func(ctx context.Context) html.Node {
+		context, _ := internal.ContextOf(ctx)
+		return html.H1(html.Text(context.Request.PathValue("slug")))
+	}))
+}
+
+func (*post) Metadata() *metadata.Metadata {
+	return &metadata.Metadata{Title: "Post"}
+}
+
+func (*post) Paths() []map[string]string {
+	return []map[string]string{{"slug": "hello"}, {"slug": "world"}}
+}
+
+func TestExport(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	build := fstest.MapFS{
+		"dist/.vite/manifest.json": {Data: []byte(`{}`)},
+		"dist/assets/main.js":      {Data: []byte(`console.log("main")`)},
+	}
+	assert.NoError(srv.SetBuildDir("dist", build, build))
+
+	srv.HandlePage("/{$}", server.PageFunc(func() html.Node {
+		return html.Body(server.Async(func(context.Context) html.Node {
+			return html.P(html.Text("resolved"))
+		}, html.Text("loading")))
+	}), nil)
+	srv.HandlePage("/blog/{slug}", &post{}, nil)
+	srv.HandlePage("/users/{id}", server.PageFunc(func() html.Node { return html.Fragment() }), nil)
+
+	dir := t.TempDir()
+	assert.NoError(srv.Export(dir))
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(err)
+		return string(data)
+	}
+	assert.Equal(`<body><p>resolved</p></body>`, read("index.html"))
+	assert.Equal(`<main><h1>hello</h1></main>`, read("blog/hello/index.html"))
+	assert.Equal(`<main><h1>world</h1></main>`, read("blog/world/index.html"))
+	assert.Equal(`console.log("main")`, read("assets/main.js"))
+	assert.Contains(read("404.html"), "Page Not Found")
+
+	_, err := os.Stat(filepath.Join(dir, "users"))
+	assert.True(os.IsNotExist(err), "pages with parameters and no paths are skipped")
+	_, err = os.Stat(filepath.Join(dir, ".vite"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestExportCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	srv.HandlePage("/{$}", server.PageFunc(func() html.Node { return html.Main() }), nil)
+
+	dir := filepath.Join(t.TempDir(), "public")
+	assert.NoError(srv.ExportCommand([]string{"-out", dir}))
+	data, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(err)
+	assert.Equal(`<main></main>`, string(data))
+
+	assert.Error(srv.ExportCommand([]string{"-out", dir, "extra"}))
+}
@@ -0,0 +1,432 @@
+// Package feed provides typed RSS 2.0 and Atom 1.0 feeds that are built from Go data.
+//
+// Example usage:
+//
+//	f := &feed.Feed{
+//		Title:   "Changelog",
+//		Link:    "https://example.com/changelog",
+//		FeedURL: "https://example.com/changelog/rss.xml",
+//		Items: []feed.Item{
+//			{Title: "v1.2.0", Link: "https://example.com/changelog/v1.2.0", Published: released},
+//		},
+//	}
+//	f.RSS(w)
+//
+// Feeds are usually served with server.HandleFeed, which also adds the alternate links of
+// the feeds to the head of every page.
+package feed
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Media types of the feed formats
+const (
+	RSSType  = "application/rss+xml"
+	AtomType = "application/atom+xml"
+)
+
+// Format is the serialization of a feed.
+type Format int
+
+const (
+	RSS = Format(iota)
+	Atom
+)
+
+// Type returns the media type of the format.
+func (f Format) Type() string {
+	if f == Atom {
+		return AtomType
+	}
+	return RSSType
+}
+
+type Author struct {
+	Name  string
+	Email string
+	URL   string
+}
+
+// Enclosure is a media file attached to an item, e.g. a podcast episode.
+type Enclosure struct {
+	URL string
+	// Media type of the file, e.g. "audio/mpeg"
+	Type string
+	// Size of the file in bytes
+	Length int64
+}
+
+type Item struct {
+	Title string
+	// URL of the page of the item
+	Link string
+	// Unique and permanent identifier of the item, defaults to the Link
+	ID string
+	// Plain text summary of the item
+	Description string
+	// HTML content of the item
+	Content    string
+	Author     *Author
+	Published  time.Time
+	Updated    time.Time
+	Categories []string
+	Enclosure  *Enclosure
+}
+
+func (i *Item) id() string {
+	if len(i.ID) > 0 {
+		return i.ID
+	}
+	return i.Link
+}
+
+type Feed struct {
+	Title       string
+	Description string
+	// URL of the site or the page the feed belongs to
+	Link string
+	// URL the feed itself is served at
+	FeedURL string
+	// Unique and permanent identifier of the feed, defaults to the FeedURL or the Link
+	ID        string
+	Language  string
+	Copyright string
+	// URL of the logo of the feed
+	Image  string
+	Author *Author
+	// Time the feed was last updated, defaults to the latest time of the items
+	Updated time.Time
+	Items   []Item
+}
+
+func (f *Feed) id() string {
+	switch {
+	case len(f.ID) > 0:
+		return f.ID
+	case len(f.FeedURL) > 0:
+		return f.FeedURL
+	}
+	return f.Link
+}
+
+// updated returns the time the feed was last updated.
+func (f *Feed) updated() time.Time {
+	updated := f.Updated
+	if !updated.IsZero() {
+		return updated
+	}
+	for _, item := range f.Items {
+		for _, t := range []time.Time{item.Published, item.Updated} {
+			if t.After(updated) {
+				updated = t
+			}
+		}
+	}
+	return updated
+}
+
+// Validate reports the missing required elements of the feed in the given format. Atom
+// additionally requires an id or a link for every item and an author for the feed or for
+// every item.
+func (f *Feed) Validate(format Format) error {
+	var err error
+	if len(f.Title) == 0 {
+		err = errors.Join(err, errors.New("feed: title is required"))
+	}
+	if len(f.Link) == 0 {
+		err = errors.Join(err, errors.New("feed: link is required"))
+	}
+	if format == Atom && len(f.Items) == 0 && atomauthor(f.Author) == nil {
+		err = errors.Join(err, errors.New("feed: author is required"))
+	}
+	for i, item := range f.Items {
+		if len(item.Title) == 0 && len(item.Description) == 0 && len(item.Content) == 0 {
+			err = errors.Join(err, errors.New("feed: item "+strconv.Itoa(i+1)+" requires a title, a description or a content"))
+		}
+		if format != Atom {
+			continue
+		}
+		if len(item.id()) == 0 {
+			err = errors.Join(err, errors.New("feed: item "+strconv.Itoa(i+1)+" requires an id or a link"))
+		}
+		if atomauthor(f.Author) == nil && atomauthor(item.Author) == nil {
+			err = errors.Join(err, errors.New("feed: item "+strconv.Itoa(i+1)+" requires an author since the feed has none"))
+		}
+	}
+	return err
+}
+
+// Write writes the feed in the given format.
+func (f *Feed) Write(w io.Writer, format Format) error {
+	if format == Atom {
+		return f.Atom(w)
+	}
+	return f.RSS(w)
+}
+
+func encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
+
+type rssguid struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssenclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int64  `xml:"length,attr"`
+}
+
+type rssitem struct {
+	Title       string        `xml:"title,omitempty"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Content     string        `xml:"content:encoded,omitempty"`
+	Author      string        `xml:"author,omitempty"`
+	Categories  []string      `xml:"category"`
+	GUID        *rssguid      `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *rssenclosure `xml:"enclosure"`
+}
+
+type rssimage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type atomlink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type rsschannel struct {
+	Title          string    `xml:"title"`
+	Link           string    `xml:"link"`
+	Description    string    `xml:"description"`
+	Self           *atomlink `xml:"atom:link"`
+	Language       string    `xml:"language,omitempty"`
+	Copyright      string    `xml:"copyright,omitempty"`
+	ManagingEditor string    `xml:"managingEditor,omitempty"`
+	LastBuildDate  string    `xml:"lastBuildDate,omitempty"`
+	Image          *rssimage `xml:"image"`
+	Items          []rssitem `xml:"item"`
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Channel rsschannel `xml:"channel"`
+}
+
+// rssdate formats the time as an RFC 822 date with a four digit year.
+func rssdate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+// rssauthor formats the author as an email address with the name, the format RSS requires.
+func rssauthor(author *Author) string {
+	if author == nil || len(author.Email) == 0 {
+		return ""
+	}
+	if len(author.Name) == 0 {
+		return author.Email
+	}
+	return author.Email + " (" + author.Name + ")"
+}
+
+// RSS writes the feed as an RSS 2.0 document, see https://www.rssboard.org/rss-specification
+func (f *Feed) RSS(w io.Writer) error {
+	if err := f.Validate(RSS); err != nil {
+		return err
+	}
+
+	channel := rsschannel{
+		Title:          f.Title,
+		Link:           f.Link,
+		Description:    f.Description,
+		Language:       f.Language,
+		Copyright:      f.Copyright,
+		ManagingEditor: rssauthor(f.Author),
+		LastBuildDate:  rssdate(f.updated()),
+		Items:          []rssitem{},
+	}
+	if len(channel.Description) == 0 {
+		// The description of the channel is required
+		channel.Description = f.Title
+	}
+	if len(f.FeedURL) > 0 {
+		channel.Self = &atomlink{Href: f.FeedURL, Rel: "self", Type: RSSType}
+	}
+	if len(f.Image) > 0 {
+		channel.Image = &rssimage{URL: f.Image, Title: f.Title, Link: f.Link}
+	}
+
+	for _, item := range f.Items {
+		entry := rssitem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Content:     item.Content,
+			Author:      rssauthor(item.Author),
+			Categories:  item.Categories,
+			PubDate:     rssdate(item.Published),
+		}
+		if id := item.id(); len(id) > 0 {
+			entry.GUID = &rssguid{Value: id, IsPermaLink: id == item.Link}
+		}
+		if len(entry.Description) == 0 && len(entry.Content) > 0 {
+			entry.Description = item.Content
+			entry.Content = ""
+		}
+		if item.Enclosure != nil {
+			entry.Enclosure = &rssenclosure{URL: item.Enclosure.URL, Type: item.Enclosure.Type, Length: item.Enclosure.Length}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return encode(w, rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Channel: channel,
+	})
+}
+
+type atomtext struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomperson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+	URI   string `xml:"uri,omitempty"`
+}
+
+type atomcategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomentry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomlink     `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomperson    `xml:"author"`
+	Categories []atomcategory `xml:"category"`
+	Summary    *atomtext      `xml:"summary"`
+	Content    *atomtext      `xml:"content"`
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"feed"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Links    []atomlink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Author   *atomperson `xml:"author"`
+	Rights   string      `xml:"rights,omitempty"`
+	Logo     string      `xml:"logo,omitempty"`
+	Entries  []atomentry `xml:"entry"`
+}
+
+func atomdate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func atomauthor(author *Author) *atomperson {
+	if author == nil || len(author.Name) == 0 {
+		return nil
+	}
+	return &atomperson{Name: author.Name, Email: author.Email, URI: author.URL}
+}
+
+// Atom writes the feed as an Atom 1.0 document, see https://www.rfc-editor.org/rfc/rfc4287
+func (f *Feed) Atom(w io.Writer) error {
+	if err := f.Validate(Atom); err != nil {
+		return err
+	}
+
+	updated := f.updated()
+	if updated.IsZero() {
+		// The updated element is required
+		updated = time.Now()
+	}
+	doc := atom{
+		XMLNS:    "http://www.w3.org/2005/Atom",
+		Lang:     f.Language,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.id(),
+		Links:    []atomlink{{Href: f.Link, Rel: "alternate", Type: "text/html"}},
+		Updated:  atomdate(updated),
+		Author:   atomauthor(f.Author),
+		Rights:   f.Copyright,
+		Logo:     f.Image,
+		Entries:  []atomentry{},
+	}
+	if len(f.FeedURL) > 0 {
+		doc.Links = append(doc.Links, atomlink{Href: f.FeedURL, Rel: "self", Type: AtomType})
+	}
+
+	for _, item := range f.Items {
+		entry := atomentry{
+			Title:      item.Title,
+			ID:         item.id(),
+			Updated:    atomdate(item.Updated),
+			Published:  atomdate(item.Published),
+			Author:     atomauthor(item.Author),
+			Categories: []atomcategory{},
+		}
+		if len(entry.Updated) == 0 {
+			entry.Updated = atomdate(item.Published)
+		}
+		if len(entry.Updated) == 0 {
+			entry.Updated = doc.Updated
+		}
+		if len(item.Link) > 0 {
+			entry.Links = append(entry.Links, atomlink{Href: item.Link, Rel: "alternate", Type: "text/html"})
+		}
+		if item.Enclosure != nil {
+			entry.Links = append(entry.Links, atomlink{Href: item.Enclosure.URL, Rel: "enclosure", Type: item.Enclosure.Type})
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomcategory{Term: category})
+		}
+		if len(item.Description) > 0 {
+			entry.Summary = &atomtext{Type: "text", Value: item.Description}
+		}
+		if len(item.Content) > 0 {
+			entry.Content = &atomtext{Type: "html", Value: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return encode(w, doc)
+}
@@ -0,0 +1,107 @@
+package feed_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/canpacis/pacis/server/feed"
+	"github.com/stretchr/testify/assert"
+)
+
+var published = time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+
+func changelog() *feed.Feed {
+	return &feed.Feed{
+		Title:   "Changelog",
+		Link:    "https://example.com/changelog",
+		FeedURL: "https://example.com/changelog/feed",
+		Author:  &feed.Author{Name: "Pacis", Email: "team@example.com"},
+		Items: []feed.Item{
+			{
+				Title:      "Tom & Jerry <v1.2>",
+				Link:       "https://example.com/changelog/v1.2",
+				Content:    "<p>Fixed <b>everything</b></p>",
+				Published:  published,
+				Categories: []string{"release"},
+			},
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(changelog().RSS(buf))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Changelog</title>
+    <link>https://example.com/changelog</link>
+    <description>Changelog</description>
+    <atom:link href="https://example.com/changelog/feed" rel="self" type="application/rss+xml"></atom:link>
+    <managingEditor>team@example.com (Pacis)</managingEditor>
+    <lastBuildDate>Tue, 04 Mar 2025 10:30:00 +0000</lastBuildDate>
+    <item>
+      <title>Tom &amp; Jerry &lt;v1.2&gt;</title>
+      <link>https://example.com/changelog/v1.2</link>
+      <description>&lt;p&gt;Fixed &lt;b&gt;everything&lt;/b&gt;&lt;/p&gt;</description>
+      <category>release</category>
+      <guid isPermaLink="true">https://example.com/changelog/v1.2</guid>
+      <pubDate>Tue, 04 Mar 2025 10:30:00 +0000</pubDate>
+    </item>
+  </channel>
+</rss>`, buf.String())
+
+	assert.ErrorContains((&feed.Feed{}).RSS(buf), "feed: title is required")
+}
+
+func TestAtom(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(changelog().Atom(buf))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Changelog</title>
+  <id>https://example.com/changelog/feed</id>
+  <link href="https://example.com/changelog" rel="alternate" type="text/html"></link>
+  <link href="https://example.com/changelog/feed" rel="self" type="application/atom+xml"></link>
+  <updated>2025-03-04T10:30:00Z</updated>
+  <author>
+    <name>Pacis</name>
+    <email>team@example.com</email>
+  </author>
+  <entry>
+    <title>Tom &amp; Jerry &lt;v1.2&gt;</title>
+    <id>https://example.com/changelog/v1.2</id>
+    <link href="https://example.com/changelog/v1.2" rel="alternate" type="text/html"></link>
+    <updated>2025-03-04T10:30:00Z</updated>
+    <published>2025-03-04T10:30:00Z</published>
+    <category term="release"></category>
+    <content type="html">&lt;p&gt;Fixed &lt;b&gt;everything&lt;/b&gt;&lt;/p&gt;</content>
+  </entry>
+</feed>`, buf.String())
+}
+
+func TestAtomValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	f := &feed.Feed{
+		Title: "Changelog",
+		Link:  "https://example.com/changelog",
+		Items: []feed.Item{{Title: "v1.2"}},
+	}
+	err := f.Atom(new(bytes.Buffer))
+	assert.ErrorContains(err, "feed: item 1 requires an id or a link")
+	assert.ErrorContains(err, "feed: item 1 requires an author since the feed has none")
+	assert.NoError(f.RSS(new(bytes.Buffer)))
+
+	f.Items[0].Link = "https://example.com/changelog/v1.2"
+	f.Items[0].Author = &feed.Author{Name: "Pacis"}
+	assert.NoError(f.Atom(new(bytes.Buffer)))
+
+	f.Items = nil
+	assert.ErrorContains(f.Atom(new(bytes.Buffer)), "feed: author is required")
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/canpacis/pacis/server/feed"
+	"github.com/canpacis/pacis/server/metadata"
+)
+
+/*
+HandleFeed serves the feed returned by fn at the given pattern. Feeds are written as Atom 1.0
+if the pattern ends with ".atom" or "atom.xml" and as RSS 2.0 otherwise. Feeds whose pattern
+has no path parameters are linked from the head of every page with a <link rel="alternate">
+element, so that browsers and feed readers can discover them.
+
+Usage:
+
+	srv.HandleFeed("/blog/rss.xml", func(r *http.Request) (*feed.Feed, error) {
+		posts, err := db.Posts(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		f := &feed.Feed{Title: "Blog", Link: "https://example.com/blog"}
+		for _, post := range posts {
+			f.Items = append(f.Items, feed.Item{Title: post.Title, Link: post.URL, Published: post.Date})
+		}
+		return f, nil
+	})
+*/
+func (s *Server) HandleFeed(pattern string, fn func(*http.Request) (*feed.Feed, error)) {
+	route := clean(pattern, "")
+	if i := strings.Index(route, "/"); i > 0 {
+		route = route[i:]
+	}
+	format := feed.RSS
+	if strings.HasSuffix(route, ".atom") || strings.HasSuffix(route, "atom.xml") {
+		format = feed.Atom
+	}
+
+	s.Handle(clean(pattern, "GET"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := fn(r)
+		if err != nil {
+			s.options.Logger.Error("Failed to build feed", "error", err, "path", r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		buf := new(bytes.Buffer)
+		if err := f.Write(buf, format); err != nil {
+			s.options.Logger.Error("Failed to write feed", "error", err, "path", r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.Type()+"; charset=utf-8")
+		buf.WriteTo(w)
+	}))
+
+	if !wildcard.MatchString(strings.ReplaceAll(route, "{$}", "")) {
+		s.feeds = append(s.feeds, metadata.Alternate{Type: format.Type(), Href: strings.ReplaceAll(route, "{$}", "")})
+	}
+}
@@ -0,0 +1,48 @@
+package server_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/server"
+	"github.com/canpacis/pacis/server/feed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleFeed(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	build := func(r *http.Request) (*feed.Feed, error) {
+		return &feed.Feed{Title: "Blog", Link: "https://example.com/blog", Author: &feed.Author{Name: "Pacis"}}, nil
+	}
+	srv.HandleFeed("/blog/rss.xml", build)
+	srv.HandleFeed("GET /blog/atom.xml", build)
+	srv.HandlePage("/{$}", server.PageFunc(func() html.Node { return html.Main() }), server.DefaultLayout)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(http.StatusOK, w.Code, path)
+		return w
+	}
+
+	rss := get("/blog/rss.xml")
+	assert.Equal("application/rss+xml; charset=utf-8", rss.Header().Get("Content-Type"))
+	assert.Contains(rss.Body.String(), `<rss version="2.0"`)
+	atom := get("/blog/atom.xml")
+	assert.Equal("application/atom+xml; charset=utf-8", atom.Header().Get("Content-Type"))
+	assert.Contains(atom.Body.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+
+	page := get("/").Body.String()
+	assert.Contains(page, `<link rel="alternate" href="/blog/rss.xml" type="application/rss+xml">`)
+	assert.Contains(page, `<link rel="alternate" href="/blog/atom.xml" type="application/atom+xml">`)
+}
@@ -50,13 +50,26 @@ func DefaultLayout(server *Server, head html.Node, children html.Node) html.Node
 	)
 }
 
-func head(page Page, devserver *url.URL, dev bool) html.Node {
+// head creates the head argument of the layout, the nodes that components register with
+// html.HeadPortal are flushed before the metadata of the page. The alternate links of the
+// feeds of the server are added to every page.
+func head(server *Server, page Page) html.Node {
+	return html.Fragment(
+		html.HeadOutlet(),
+		metahead(page, server.options.DevServer, server.options.Env == Dev),
+		html.Component(func(context.Context) html.Node {
+			return html.Map(server.feeds, metadata.Alternate.Node)
+		}),
+	)
+}
+
+func metahead(page Page, devserver *url.URL, dev bool) html.Node {
 	staticmeta, ok := page.(interface{ Metadata() *metadata.Metadata })
 	if ok {
 		if dev {
 			return html.Fragment(
 				staticmeta.Metadata().Node(),
-				html.Script(html.Type("module"), html.Src(devserver.String()+"/@vite/client")),
+				viteclient(devserver),
 			)
 		}
 		return staticmeta.Metadata().Node()
@@ -72,7 +85,7 @@ func head(page Page, devserver *url.URL, dev bool) html.Node {
 				html.Component(func(ctx context.Context) html.Node {
 					return dynamicmeta.Metadata(ctx).Node()
 				}),
-				html.Script(html.Type("module"), html.Src(devserver.String()+"/@vite/client")),
+				viteclient(devserver),
 			)
 		}
 		return html.Component(func(ctx context.Context) html.Node {
@@ -81,6 +94,15 @@ func head(page Page, devserver *url.URL, dev bool) html.Node {
 	}
 }
 
+// viteclient creates the script of the Vite dev server, the csp-nonce meta element lets
+// Vite add the nonce of the request to the styles it injects.
+func viteclient(devserver *url.URL) html.Node {
+	return html.Fragment(
+		html.Meta(html.PropertyAttr("csp-nonce"), html.NonceAttr()),
+		html.Script(html.Type("module"), html.Src(devserver.String()+"/@vite/client")),
+	)
+}
+
 var bufpool = sync.Pool{
 	New: func() any {
 		return new(bytes.Buffer)
@@ -110,7 +132,7 @@ Returns:
   - http.Handler: The composed HTTP handler ready to be registered with a router or server.
 */
 func PageHandler(server *Server, page Page, layout Layout, middlewares ...middleware.Middleware) http.Handler {
-	var handler = handler(server, page, layout, false)
+	var handler = handler(server, page, layout, false, server.csp(middlewares))
 
 	for i := len(server.middlewares) - 1; i >= 0; i-- {
 		handler = server.middlewares[i].Apply(handler)
@@ -122,7 +144,9 @@ func PageHandler(server *Server, page Page, layout Layout, middlewares ...middle
 	return handler
 }
 
-func handler(server *Server, page Page, layout Layout, internal bool) http.Handler {
+// handler builds the page once and returns the handler that renders it, nonce reports
+// whether the page is served with the CSP middleware, see html.EnableNonce.
+func handler(server *Server, page Page, layout Layout, internal bool, nonce bool) http.Handler {
 	defer func() {
 		if data := recover(); data != nil {
 			server.options.Logger.Error("HTTP handler paniced on partial pre-render", "error", data)
@@ -133,24 +157,45 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 	if wrapper == nil {
 		wrapper = func(s *Server, h, c html.Node) html.Node { return c }
 	}
-	node := wrapper(server, head(page, server.options.DevServer, server.options.Env == Dev), page.Page())
+	node := wrapper(server, head(server, page), page.Page())
 
-	renderer := NewStaticRenderer()
+	renderer := NewStaticRenderer().WithMode(server.options.RenderMode).WithErrorReporter(server.report).WithNonce(nonce)
+	if server.options.Validate {
+		renderer.WithValidator(func(err error) error {
+			return server.validate(page, err)
+		})
+	}
 	if err := renderer.Build(node); err != nil {
-		log.Fatalf("Failed to statically render page: %s", err.Error())
+		server.fail(page, err)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		})
 	}
 
+	var warn sync.Once
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if ((r.Pattern == "/" || r.Pattern == "GET /") && r.URL.Path != "/") && !internal {
 			server.notfound.ServeHTTP(w, r)
 			return
 		}
+		if !nonce && len(html.Nonce(r.Context())) > 0 {
+			warn.Do(func() {
+				server.options.Logger.Warn("Page is served with a nonce but was built without one, use the CSP middleware before the page is registered", "path", r.URL.Path)
+			})
+		}
 		ctx := intserver.NewContext(w, r)
+		ctx.Context = html.WithErrorReporter(ctx.Context, func(err error) {
+			server.options.Logger.Error("Error boundary caught an error", "error", err, "path", r.URL.Path)
+		})
+		ctx.Context = html.WithResponseScope(ctx.Context)
 
-		buf := bufpool.New().(*bytes.Buffer)
+		buf := bufpool.Get().(*bytes.Buffer)
+		buf.Reset()
 		defer bufpool.Put(buf)
 
 		if err := renderer.Render(ctx, buf); err != nil {
+			server.options.Logger.Error("Failed to render page", "error", err, "path", r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 
@@ -163,6 +208,8 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 			return
 		}
 
+		body := html.ResolveHoisted(ctx, buf.Bytes())
+
 		if internal {
 			w.WriteHeader(http.StatusNotFound)
 		} else {
@@ -170,9 +217,9 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 		}
 		w.Header().Set("Content-Type", "text/html")
 		if len(ctx.AsyncChunks) == 0 {
-			w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
 		}
-		io.Copy(w, buf)
+		w.Write(body)
 
 		if len(ctx.AsyncChunks) == 0 {
 			return
@@ -184,7 +231,7 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 		}
 		flusher.Flush()
 
-		renderers := make(chan *StaticRenderer)
+		renderers := make(chan asyncrenderer)
 		wg := sync.WaitGroup{}
 
 		for _, chunk := range ctx.AsyncChunks {
@@ -192,14 +239,16 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 			go func(chunk intserver.AsyncChunk) {
 				defer wg.Done()
 
-				renderer := NewStaticRenderer()
+				renderer := NewStaticRenderer().WithMode(server.options.RenderMode).WithErrorReporter(server.report).WithNonce(nonce)
 				var node html.Node
-				node = chunk.Component(ctx)
+				node = chunk.Component(chunk.Context)
 				elem, ok := node.(*html.Element)
 				if !ok {
 					node = html.Template(html.SlotAttr(chunk.ID), node)
 				} else {
+					elem = elem.Mutable()
 					elem.SetAttribute("slot", chunk.ID)
+					node = elem
 				}
 
 				if err := renderer.Build(node); err != nil {
@@ -207,7 +256,7 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 					return
 				}
 
-				renderers <- renderer
+				renderers <- asyncrenderer{renderer, chunk.Context}
 			}(chunk)
 		}
 
@@ -217,39 +266,91 @@ func handler(server *Server, page Page, layout Layout, internal bool) http.Handl
 		}()
 
 		for renderer := range renderers {
-			renderer.Render(ctx, w)
+			if err := renderer.Render(renderer.ctx, w); err != nil {
+				server.options.Logger.Error("Failed to render async chunk", "error", err, "path", r.URL.Path)
+			}
 			flusher.Flush()
 		}
 	})
 }
 
+// asyncrenderer is a renderer of an async chunk along with the context the chunk is rendered with.
+type asyncrenderer struct {
+	*StaticRenderer
+	ctx context.Context
+}
+
 type StaticRenderer struct {
-	chunks []any
+	chunks    []any
+	mode      html.RenderMode
+	validator func(error) error
+	reporter  func(error)
+	nonce     bool
+}
+
+// Sets the render mode the static chunks are formatted with and returns the renderer back.
+// Formatting happens once in Build, not on every Render.
+func (r *StaticRenderer) WithMode(mode html.RenderMode) *StaticRenderer {
+	r.mode = mode
+	return r
+}
+
+// Sets the validator of the renderer and returns the renderer back. Build validates the
+// content models of the node with html.Validate and calls fn with the error if there are
+// violations, the build fails if fn returns an error.
+func (r *StaticRenderer) WithValidator(fn func(error) error) *StaticRenderer {
+	r.validator = fn
+	return r
+}
+
+// Sets whether the <script> and <style> elements get the nonce of the render context and
+// returns the renderer back, see html.EnableNonce. Pages served with the CSP middleware
+// enable it, others keep their static output in fewer chunks.
+func (r *StaticRenderer) WithNonce(enabled bool) *StaticRenderer {
+	r.nonce = enabled
+	return r
 }
 
+// Sets the function that is called with the errors that error boundaries catch while
+// the node is built and returns the renderer back, see html.SetErrorReporter.
+func (r *StaticRenderer) WithErrorReporter(fn func(error)) *StaticRenderer {
+	r.reporter = fn
+	return r
+}
+
+// Build renders the static parts of the node once, adjacent static chunks are already
+// coalesced by the chunk writer. The node is not released since the dynamic chunks keep
+// referencing it and pages share nodes with each other (e.g. html.Doctype).
 func (r *StaticRenderer) Build(node html.Node) error {
-	buf := new(bytes.Buffer)
-	cw := html.NewChunkWriter()
-	node.Render(cw)
-	defer node.Release()
+	if r.validator != nil {
+		if err := html.Validate(node); err != nil {
+			if err := r.validator(err); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw := html.NewChunkWriterWithMode(r.mode)
+	if r.reporter != nil {
+		html.SetErrorReporter(cw, r.reporter)
+	}
+	if r.nonce {
+		html.EnableNonce(cw)
+	}
+	if err := node.Render(cw); err != nil {
+		return err
+	}
 
 	for _, chunk := range cw.Chunks() {
 		switch chunk := chunk.(type) {
 		case html.StaticChunk:
-			if _, err := buf.Write(chunk); err != nil {
-				return err
-			}
+			r.chunks = append(r.chunks, []byte(chunk))
 		case html.DynamicChunk:
-			r.chunks = append(r.chunks, buf.Bytes())
-			bufpool.Put(buf)
-			buf = bufpool.New().(*bytes.Buffer)
 			r.chunks = append(r.chunks, chunk)
 		default:
 			return fmt.Errorf("invalid chunk type %T", chunk)
 		}
 	}
-
-	r.chunks = append(r.chunks, buf.Bytes())
 	return nil
 }
 
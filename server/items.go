@@ -19,11 +19,15 @@ func Async(comp html.Component, fallback html.Node) html.Component {
 	}
 
 	return html.Component(func(ctx context.Context) html.Node {
-		context, ok := ctx.(*internal.Context)
+		context, ok := internal.ContextOf(ctx)
+		if ok && context.InlineAsync {
+			return comp(ctx)
+		}
 		if ok {
 			context.AsyncChunks = append(context.AsyncChunks, internal.AsyncChunk{
 				ID:        id,
 				Component: comp,
+				Context:   ctx,
 			})
 		}
 		return html.Slot(html.Name(id), fallback)
@@ -31,7 +35,7 @@ func Async(comp html.Component, fallback html.Node) html.Component {
 }
 
 func Data[T any](ctx context.Context) (*T, error) {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if !ok {
 		return nil, fmt.Errorf("Data helper used outside of server rendering context")
 	}
@@ -81,7 +85,7 @@ func (d *RequestDetail) Cookie(name string) (*http.Cookie, error) {
 }
 
 func Detail(ctx context.Context) (*RequestDetail, error) {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if !ok {
 		return nil, fmt.Errorf("Detail helper used outside of server rendering context")
 	}
@@ -98,7 +102,7 @@ func Detail(ctx context.Context) (*RequestDetail, error) {
 }
 
 func Redirect(ctx context.Context, to string) html.Node {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if ok {
 		context.RedirectMark = &internal.RedirectMark{Status: http.StatusFound, To: to}
 	} else {
@@ -114,7 +118,7 @@ func RedirectComponent(to string) html.Component {
 }
 
 func RedirectWith(ctx context.Context, to string, status int) html.Node {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if ok {
 		context.RedirectMark = &internal.RedirectMark{Status: status, To: to}
 	} else {
@@ -130,7 +134,7 @@ func RedirectWithComponent(to string, status int) html.Component {
 }
 
 func NotFound(ctx context.Context) html.Node {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if ok {
 		context.NotFoundMark = true
 	} else {
@@ -140,7 +144,7 @@ func NotFound(ctx context.Context) html.Node {
 }
 
 func SetCookie(ctx context.Context, cookie *http.Cookie) html.Node {
-	context, ok := ctx.(*internal.Context)
+	context, ok := internal.ContextOf(ctx)
 	if ok {
 		http.SetCookie(context.ResponseWriter, cookie)
 	} else {
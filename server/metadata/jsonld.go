@@ -0,0 +1,465 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/canpacis/pacis/html"
+)
+
+/*
+StructuredData is a schema.org type that is rendered as a JSON-LD script by Metadata.Node,
+see https://developers.google.com/search/docs/appearance/structured-data/intro-structured-data
+
+The required properties are validated when the data is encoded, so a page with invalid
+structured data fails to render instead of silently losing its rich results: static
+metadata fails when the page is registered and the metadata of a Metadata(ctx) method
+fails the request.
+*/
+type StructuredData interface {
+	json.Marshaler
+	// Validate reports the missing required properties of the data.
+	Validate() error
+}
+
+// props builds the JSON-LD object of a type, empty values are omitted.
+type props map[string]any
+
+func (p props) set(key string, value any) props {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+		return p
+	}
+	p[key] = value
+	return p
+}
+
+func (p props) encode(typ string, data StructuredData) ([]byte, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	p["@type"] = typ
+	return json.Marshal(map[string]any(p))
+}
+
+func required(typ string, pairs ...any) error {
+	var err error
+	for i := 0; i < len(pairs); i += 2 {
+		v := reflect.ValueOf(pairs[i+1])
+		if !v.IsValid() || v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+			err = errors.Join(err, fmt.Errorf("metadata: %s requires the %s property", typ, pairs[i]))
+		}
+	}
+	return err
+}
+
+// jsonld adds the schema.org context to the top level object of structured data.
+type jsonld struct {
+	data StructuredData
+}
+
+func (j jsonld) MarshalJSON() ([]byte, error) {
+	data, err := j.data.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("metadata: structured data of type %T is not a JSON object", j.data)
+	}
+	context := `{"@context":"https://schema.org"`
+	if len(data) > 2 {
+		context += ","
+	}
+	return append([]byte(context), data[1:]...), nil
+}
+
+// https://schema.org/Organization
+type Organization struct {
+	Name        string
+	URL         string
+	Logo        string
+	Description string
+	Email       string
+	Telephone   string
+	// URLs of the profiles of the organization on other sites
+	SameAs []string
+}
+
+func (o *Organization) Validate() error {
+	return required("Organization", "name", o.Name)
+}
+
+func (o *Organization) MarshalJSON() ([]byte, error) {
+	return props{}.
+		set("name", o.Name).
+		set("url", o.URL).
+		set("logo", o.Logo).
+		set("description", o.Description).
+		set("email", o.Email).
+		set("telephone", o.Telephone).
+		set("sameAs", o.SameAs).
+		encode("Organization", o)
+}
+
+// https://schema.org/Person
+type Person struct {
+	Name string
+	URL  string
+}
+
+func (p *Person) Validate() error {
+	return required("Person", "name", p.Name)
+}
+
+func (p *Person) MarshalJSON() ([]byte, error) {
+	return props{}.set("name", p.Name).set("url", p.URL).encode("Person", p)
+}
+
+// https://schema.org/WebSite
+type WebSite struct {
+	Name string
+	URL  string
+	// Enables the sitelinks search box of the site
+	SearchAction *SearchAction
+}
+
+func (w *WebSite) Validate() error {
+	err := required("WebSite", "name", w.Name, "url", w.URL)
+	if w.SearchAction != nil {
+		err = errors.Join(err, w.SearchAction.Validate())
+	}
+	return err
+}
+
+func (w *WebSite) MarshalJSON() ([]byte, error) {
+	return props{}.
+		set("name", w.Name).
+		set("url", w.URL).
+		set("potentialAction", w.SearchAction).
+		encode("WebSite", w)
+}
+
+// https://schema.org/SearchAction
+type SearchAction struct {
+	// URL template of the search results page, e.g. "https://example.com/search?q={search_term_string}"
+	Target string
+	// Name of the placeholder in the target, defaults to "search_term_string"
+	QueryName string
+}
+
+func (s *SearchAction) query() string {
+	if len(s.QueryName) == 0 {
+		return "search_term_string"
+	}
+	return s.QueryName
+}
+
+func (s *SearchAction) Validate() error {
+	if err := required("SearchAction", "target", s.Target); err != nil {
+		return err
+	}
+	if !strings.Contains(s.Target, "{"+s.query()+"}") {
+		return fmt.Errorf("metadata: SearchAction target %q does not contain the {%s} placeholder", s.Target, s.query())
+	}
+	return nil
+}
+
+func (s *SearchAction) MarshalJSON() ([]byte, error) {
+	return props{}.
+		set("target", map[string]string{"@type": "EntryPoint", "urlTemplate": s.Target}).
+		set("query-input", "required name="+s.query()).
+		encode("SearchAction", s)
+}
+
+// https://schema.org/Article
+type Article struct {
+	// One of "Article", "NewsArticle" or "BlogPosting", defaults to "Article"
+	Type          string
+	Headline      string
+	Description   string
+	URL           string
+	Images        []string
+	DatePublished time.Time
+	DateModified  time.Time
+	Authors       []Person
+	Publisher     *Organization
+}
+
+func (a *Article) typ() string {
+	if len(a.Type) == 0 {
+		return "Article"
+	}
+	return a.Type
+}
+
+func (a *Article) Validate() error {
+	err := required(a.typ(), "headline", a.Headline)
+	for i := range a.Authors {
+		err = errors.Join(err, a.Authors[i].Validate())
+	}
+	if a.Publisher != nil {
+		err = errors.Join(err, a.Publisher.Validate())
+	}
+	return err
+}
+
+func (a *Article) MarshalJSON() ([]byte, error) {
+	authors := make([]*Person, len(a.Authors))
+	for i := range a.Authors {
+		authors[i] = &a.Authors[i]
+	}
+	return props{}.
+		set("headline", a.Headline).
+		set("description", a.Description).
+		set("mainEntityOfPage", a.URL).
+		set("image", a.Images).
+		set("datePublished", a.DatePublished).
+		set("dateModified", a.DateModified).
+		set("author", authors).
+		set("publisher", a.Publisher).
+		encode(a.typ(), a)
+}
+
+// Item availabilities of offers, see https://schema.org/ItemAvailability
+const (
+	InStock             = "https://schema.org/InStock"
+	OutOfStock          = "https://schema.org/OutOfStock"
+	PreOrder            = "https://schema.org/PreOrder"
+	BackOrder           = "https://schema.org/BackOrder"
+	Discontinued        = "https://schema.org/Discontinued"
+	LimitedAvailability = "https://schema.org/LimitedAvailability"
+	SoldOut             = "https://schema.org/SoldOut"
+)
+
+// https://schema.org/Product
+type Product struct {
+	Name        string
+	Description string
+	Images      []string
+	SKU         string
+	Brand       string
+	Offers      []Offer
+}
+
+func (p *Product) Validate() error {
+	err := required("Product", "name", p.Name, "offers", p.Offers)
+	for i := range p.Offers {
+		err = errors.Join(err, p.Offers[i].Validate())
+	}
+	return err
+}
+
+func (p *Product) MarshalJSON() ([]byte, error) {
+	var brand any
+	if len(p.Brand) > 0 {
+		brand = map[string]string{"@type": "Brand", "name": p.Brand}
+	}
+	offers := make([]*Offer, len(p.Offers))
+	for i := range p.Offers {
+		offers[i] = &p.Offers[i]
+	}
+	return props{}.
+		set("name", p.Name).
+		set("description", p.Description).
+		set("image", p.Images).
+		set("sku", p.SKU).
+		set("brand", brand).
+		set("offers", offers).
+		encode("Product", p)
+}
+
+// https://schema.org/Offer
+type Offer struct {
+	// Price without the currency symbol, e.g. "19.99"
+	Price string
+	// ISO 4217 currency code, e.g. "USD"
+	PriceCurrency string
+	// One of the item availabilities, e.g. InStock
+	Availability    string
+	URL             string
+	PriceValidUntil time.Time
+}
+
+func (o *Offer) Validate() error {
+	return required("Offer", "price", o.Price, "priceCurrency", o.PriceCurrency)
+}
+
+func (o *Offer) MarshalJSON() ([]byte, error) {
+	var until string
+	if !o.PriceValidUntil.IsZero() {
+		until = o.PriceValidUntil.Format(time.DateOnly)
+	}
+	return props{}.
+		set("price", o.Price).
+		set("priceCurrency", o.PriceCurrency).
+		set("availability", o.Availability).
+		set("url", o.URL).
+		set("priceValidUntil", until).
+		encode("Offer", o)
+}
+
+// https://schema.org/BreadcrumbList
+type BreadcrumbList struct {
+	Items []Breadcrumb
+}
+
+// A single item of a BreadcrumbList, its position is the index in the list.
+type Breadcrumb struct {
+	Name string
+	// URL of the item, can be omitted for the last item that represents the current page
+	URL string
+}
+
+func (b *BreadcrumbList) Validate() error {
+	err := required("BreadcrumbList", "itemListElement", b.Items)
+	for i, item := range b.Items {
+		if len(item.Name) == 0 {
+			err = errors.Join(err, fmt.Errorf("metadata: BreadcrumbList item %d requires the name property", i+1))
+		}
+		if len(item.URL) == 0 && i < len(b.Items)-1 {
+			err = errors.Join(err, fmt.Errorf("metadata: BreadcrumbList item %d requires the item property", i+1))
+		}
+	}
+	return err
+}
+
+func (b *BreadcrumbList) MarshalJSON() ([]byte, error) {
+	items := make([]props, len(b.Items))
+	for i, item := range b.Items {
+		items[i] = props{"@type": "ListItem", "position": i + 1}.set("name", item.Name).set("item", item.URL)
+	}
+	return props{}.set("itemListElement", items).encode("BreadcrumbList", b)
+}
+
+// https://schema.org/FAQPage
+type FAQPage struct {
+	Questions []Question
+}
+
+// A question of a FAQPage along with its accepted answer.
+type Question struct {
+	Question string
+	// Text of the answer, it may contain basic HTML
+	Answer string
+}
+
+func (f *FAQPage) Validate() error {
+	err := required("FAQPage", "mainEntity", f.Questions)
+	for i, question := range f.Questions {
+		if len(question.Question) == 0 || len(question.Answer) == 0 {
+			err = errors.Join(err, fmt.Errorf("metadata: FAQPage question %d requires the name and the acceptedAnswer properties", i+1))
+		}
+	}
+	return err
+}
+
+func (f *FAQPage) MarshalJSON() ([]byte, error) {
+	questions := make([]props, len(f.Questions))
+	for i, question := range f.Questions {
+		questions[i] = props{
+			"@type":          "Question",
+			"name":           question.Question,
+			"acceptedAnswer": props{"@type": "Answer", "text": question.Answer},
+		}
+	}
+	return props{}.set("mainEntity", questions).encode("FAQPage", f)
+}
+
+// Statuses of events, see https://schema.org/EventStatusType
+const (
+	EventScheduled   = "https://schema.org/EventScheduled"
+	EventCancelled   = "https://schema.org/EventCancelled"
+	EventPostponed   = "https://schema.org/EventPostponed"
+	EventRescheduled = "https://schema.org/EventRescheduled"
+	EventMovedOnline = "https://schema.org/EventMovedOnline"
+)
+
+// Attendance modes of events, see https://schema.org/EventAttendanceModeEnumeration
+const (
+	OfflineAttendance = "https://schema.org/OfflineEventAttendanceMode"
+	OnlineAttendance  = "https://schema.org/OnlineEventAttendanceMode"
+	MixedAttendance   = "https://schema.org/MixedEventAttendanceMode"
+)
+
+// https://schema.org/Event
+type Event struct {
+	Name        string
+	Description string
+	Images      []string
+	StartDate   time.Time
+	EndDate     time.Time
+	// One of the event statuses, e.g. EventScheduled
+	Status string
+	// One of the attendance modes, e.g. OfflineAttendance
+	AttendanceMode string
+	// The physical location of the event
+	Location *Place
+	// The URL of an online event
+	OnlineURL string
+	Organizer *Organization
+	Offers    []Offer
+}
+
+// https://schema.org/Place
+type Place struct {
+	Name    string
+	Address string
+}
+
+func (e *Event) Validate() error {
+	err := required("Event", "name", e.Name, "startDate", e.StartDate)
+	if e.Location == nil && len(e.OnlineURL) == 0 {
+		err = errors.Join(err, errors.New("metadata: Event requires the location property, set either the Location or the OnlineURL"))
+	}
+	if e.Location != nil && len(e.Location.Address) == 0 {
+		err = errors.Join(err, errors.New("metadata: Place requires the address property"))
+	}
+	if e.Organizer != nil {
+		err = errors.Join(err, e.Organizer.Validate())
+	}
+	for i := range e.Offers {
+		err = errors.Join(err, e.Offers[i].Validate())
+	}
+	return err
+}
+
+func (e *Event) MarshalJSON() ([]byte, error) {
+	locations := []props{}
+	if e.Location != nil {
+		locations = append(locations, props{"@type": "Place", "address": e.Location.Address}.set("name", e.Location.Name))
+	}
+	if len(e.OnlineURL) > 0 {
+		locations = append(locations, props{"@type": "VirtualLocation", "url": e.OnlineURL})
+	}
+	var location any = locations
+	if len(locations) == 1 {
+		location = locations[0]
+	}
+	offers := make([]*Offer, len(e.Offers))
+	for i := range e.Offers {
+		offers[i] = &e.Offers[i]
+	}
+	return props{}.
+		set("name", e.Name).
+		set("description", e.Description).
+		set("image", e.Images).
+		set("startDate", e.StartDate).
+		set("endDate", e.EndDate).
+		set("eventStatus", e.Status).
+		set("eventAttendanceMode", e.AttendanceMode).
+		set("location", location).
+		set("organizer", e.Organizer).
+		set("offers", offers).
+		encode("Event", e)
+}
+
+// structureddata renders the structured data as JSON-LD scripts.
+func structureddata(data []StructuredData) html.Node {
+	return html.Map(data, func(data StructuredData) html.Node {
+		return html.Script(html.Type("application/ld+json"), html.JSON(jsonld{data}))
+	})
+}
@@ -0,0 +1,81 @@
+package metadata_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/server/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(node html.Node) (string, error) {
+	cw := html.NewChunkWriter()
+	if err := node.Render(cw); err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	for _, chunk := range cw.Chunks() {
+		if err := html.Render(chunk, context.Background(), buf); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+func TestStructuredData(t *testing.T) {
+	assert := assert.New(t)
+
+	meta := &metadata.Metadata{
+		StructuredData: []metadata.StructuredData{
+			&metadata.WebSite{
+				Name:         "Pacis",
+				URL:          "https://pacis.dev",
+				SearchAction: &metadata.SearchAction{Target: "https://pacis.dev/search?q={search_term_string}"},
+			},
+			&metadata.Product{
+				Name:   "Shirt </script>",
+				Brand:  "Pacis",
+				Offers: []metadata.Offer{{Price: "19.99", PriceCurrency: "USD", Availability: metadata.InStock}},
+			},
+			&metadata.BreadcrumbList{Items: []metadata.Breadcrumb{{Name: "Home", URL: "/"}, {Name: "Shirts"}}},
+			&metadata.Event{
+				Name:      "Launch",
+				StartDate: time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC),
+				OnlineURL: "https://pacis.dev/live",
+			},
+		},
+	}
+	assert.NoError(meta.Validate())
+
+	rendered, err := render(meta.Node())
+	assert.NoError(err)
+	assert.Equal(
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"WebSite","name":"Pacis","potentialAction":{"@type":"SearchAction","query-input":"required name=search_term_string","target":{"@type":"EntryPoint","urlTemplate":"https://pacis.dev/search?q={search_term_string}"}},"url":"https://pacis.dev"}
+</script>`+
+			`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Product","brand":{"@type":"Brand","name":"Pacis"},"name":"Shirt \u003c/script\u003e","offers":[{"@type":"Offer","availability":"https://schema.org/InStock","price":"19.99","priceCurrency":"USD"}]}
+</script>`+
+			`<script type="application/ld+json">{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[{"@type":"ListItem","item":"/","name":"Home","position":1},{"@type":"ListItem","name":"Shirts","position":2}]}
+</script>`+
+			`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Event","location":{"@type":"VirtualLocation","url":"https://pacis.dev/live"},"name":"Launch","startDate":"2025-01-02T18:00:00Z"}
+</script>`,
+		rendered,
+	)
+
+	invalid := &metadata.Metadata{
+		StructuredData: []metadata.StructuredData{
+			&metadata.Product{Name: "Shirt"},
+			&metadata.FAQPage{Questions: []metadata.Question{{Question: "Why?"}}},
+			&metadata.WebSite{Name: "Pacis", URL: "/", SearchAction: &metadata.SearchAction{Target: "/search"}},
+		},
+	}
+	err = invalid.Validate()
+	assert.ErrorContains(err, "Product requires the offers property")
+	assert.ErrorContains(err, "FAQPage question 1 requires the name and the acceptedAnswer properties")
+	assert.ErrorContains(err, "does not contain the {search_term_string} placeholder")
+
+	_, err = render(invalid.Node())
+	assert.ErrorContains(err, "Product requires the offers property")
+}
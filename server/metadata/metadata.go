@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"net/url"
@@ -22,11 +23,27 @@ type Alternate struct {
 	Media    string
 }
 
+// Node renders the alternate as a <link rel="alternate"> element.
+func (a Alternate) Node() html.Node {
+	return html.Link(
+		html.Rel("alternate"),
+		html.If(len(a.Href) > 0, html.Href(a.Href)),
+		html.If(len(a.HrefLang) > 0, html.HrefLang(a.HrefLang)),
+		html.If(len(a.Type) > 0, html.Type(a.Type)),
+		html.If(len(a.Media) > 0, html.Media(a.Media)),
+		html.If(len(a.Title) > 0, html.TitleAttr(a.Title)),
+	)
+}
+
 type Robots struct {
 	Index   bool
 	Follow  bool
 	Nocache bool
 	Other   map[string]map[string]any
+	// Disallows crawling the page in the robots.txt served by the server, it is not rendered.
+	// Pages that are not indexed should stay crawlable, crawlers must fetch a page to see its
+	// noindex directive.
+	Disallow bool
 }
 
 func (r *Robots) Node() html.Node {
@@ -139,29 +156,35 @@ type Metadata struct {
 	Robots          *Robots
 	OpenGraph       *OpenGraph
 	Twitter         *Twitter
+	// schema.org types rendered as JSON-LD scripts, e.g. &metadata.Article{...}
+	StructuredData []StructuredData
+}
+
+// Validate reports the missing required properties of the structured data of the metadata.
+func (m *Metadata) Validate() error {
+	var err error
+	for _, data := range m.StructuredData {
+		err = errors.Join(err, data.Validate())
+	}
+	return err
 }
 
+// Node renders the metadata as head elements. The title, the description and the canonical
+// link are registered with html.HeadPortal under the keys "title", "description" and
+// "canonical", so the components of a page can override them. Structured data is rendered
+// as JSON-LD scripts and fails the render if it is invalid, see Validate.
 func (m *Metadata) Node() html.Node {
 	return html.Fragment(
-		html.If(len(m.Title) > 0, html.Title(html.Text(m.Title))).(html.Node),
-		html.If(len(m.Description) > 0, html.Meta(html.Name("description"), html.Content(m.Description))).(html.Node),
+		html.If(len(m.Title) > 0, html.HeadPortal("title", html.Title(html.Text(m.Title)))).(html.Node),
+		html.If(len(m.Description) > 0, html.HeadPortal("description", html.Meta(html.Name("description"), html.Content(m.Description)))).(html.Node),
 		html.If(len(m.ApplicationName) > 0, html.Meta(html.Name("application-name"), html.Content(m.ApplicationName))).(html.Node),
 		html.If(len(m.Generator) > 0, html.Meta(html.Name("generator"), html.Content(m.Generator))).(html.Node),
 		html.If(len(m.Keywords) > 0, html.Meta(html.Name("keywords"), html.Content(strings.Join(m.Keywords, ",")))).(html.Node),
 		html.If(len(m.Referrer) > 0, html.Meta(html.Name("referrer"), html.Content(m.Referrer))).(html.Node),
 		html.If(len(m.Creator) > 0, html.Meta(html.Name("creator"), html.Content(m.Creator))).(html.Node),
 		html.If(len(m.Publisher) > 0, html.Meta(html.Name("publisher"), html.Content(m.Publisher))).(html.Node),
-		html.If(len(m.Canonical) > 0, html.Link(html.Rel("canonical"), html.Href(m.Canonical))).(html.Node),
-		html.Map(m.Alternates, func(alternate Alternate) html.Node {
-			return html.Link(
-				html.Rel("alternate"),
-				html.If(len(alternate.Href) > 0, html.Href(alternate.Href)),
-				html.If(len(alternate.HrefLang) > 0, html.HrefLang(alternate.HrefLang)),
-				html.If(len(alternate.Type) > 0, html.Type(alternate.Type)),
-				html.If(len(alternate.Media) > 0, html.Media(alternate.Media)),
-				html.If(len(alternate.Title) > 0, html.TitleAttr(alternate.Title)),
-			)
-		}),
+		html.If(len(m.Canonical) > 0, html.HeadPortal("canonical", html.Link(html.Rel("canonical"), html.Href(m.Canonical)))).(html.Node),
+		html.Map(m.Alternates, Alternate.Node),
 		html.Map(m.Authors, func(author Author) html.Node {
 			return html.Fragment(
 				html.Meta(html.Name("author"), html.Content(author.Name)),
@@ -177,5 +200,6 @@ func (m *Metadata) Node() html.Node {
 		html.IfFn(m.Twitter != nil, func() html.Item {
 			return m.Twitter.Node()
 		}).(html.Node),
+		structureddata(m.StructuredData),
 	)
 }
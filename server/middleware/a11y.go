@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/html/a11y"
+)
+
+var closebody = []byte("</body>")
+
+type a11ywriter struct {
+	http.ResponseWriter
+	overlay bool
+	buf     *bytes.Buffer
+	// Whether the header is written
+	written bool
+	// Whether the response is buffered to be linted, and the overlay inserted into it,
+	// decided once the header is written
+	lint   bool
+	insert bool
+	// Length of the buffered output that is written to the response
+	sent int
+	// The output from the closing </body> tag on, held back until the overlay is written
+	tail []byte
+}
+
+func (w *a11ywriter) WriteHeader(code int) {
+	if !w.written {
+		header := w.Header()
+		// Encoded responses can not be linted or changed
+		w.lint = ishtml(header.Get("Content-Type")) && len(header.Get("Content-Encoding")) == 0
+		w.insert = w.lint && w.overlay
+		if w.insert {
+			// The overlay is inserted into the body
+			header.Del("Content-Length")
+		}
+	}
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *a11ywriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.lint {
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf.Write(p)
+	if !w.insert || w.tail != nil {
+		// Async chunks are written before the held back closing tags
+		return w.ResponseWriter.Write(p)
+	}
+
+	// The closing tag may be split across writes, so it is searched in the buffered output
+	// and a partial tag at the end is held back until the next write
+	pending := w.buf.Bytes()[w.sent:]
+	if i := bytes.Index(pending, closebody); i >= 0 {
+		w.tail = bytes.Clone(pending[i:])
+		pending = pending[:i]
+	} else {
+		pending = pending[:len(pending)-partial(pending)]
+	}
+	w.sent += len(pending)
+	if _, err := w.ResponseWriter.Write(pending); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// finish writes the output that is held back for a partial closing tag, if the response
+// ends without a </body> tag.
+func (w *a11ywriter) finish() {
+	if w.insert && w.tail == nil && w.sent < w.buf.Len() {
+		w.ResponseWriter.Write(w.buf.Bytes()[w.sent:])
+		w.sent = w.buf.Len()
+	}
+}
+
+// partial returns the length of the longest suffix of p that is the start of a </body> tag.
+func partial(p []byte) int {
+	for n := min(len(p), len(closebody)-1); n > 0; n-- {
+		if bytes.HasSuffix(p, closebody[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+func (w *a11ywriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ishtml reports whether the content type is html, an empty content type may be sniffed as html.
+func ishtml(contenttype string) bool {
+	return len(contenttype) == 0 || strings.HasPrefix(contenttype, "text/html")
+}
+
+/*
+A11y is a development middleware that lints the HTML responses for accessibility issues,
+see the a11y package. The issues are logged as warnings and, if overlay is set, listed in
+a dialog at the end of the <body> element of full documents, fragments never get the
+overlay. The responses are still streamed, they are linted once they are complete and
+the closing </body> tag is held back until then. Responses that are not html or that have a
+Content-Encoding are passed through without being linted.
+
+The server uses it in the development environment.
+
+Usage:
+
+	srv.Use(middleware.NewA11y(logger, true))
+*/
+type A11y struct {
+	logger  *slog.Logger
+	overlay bool
+}
+
+func (*A11y) Name() string {
+	return "A11y"
+}
+
+func (m *A11y) Apply(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aw := &a11ywriter{ResponseWriter: w, overlay: m.overlay, buf: new(bytes.Buffer)}
+		h.ServeHTTP(aw, r)
+		aw.finish()
+		if aw.tail != nil {
+			defer w.Write(aw.tail)
+		}
+		if !aw.lint {
+			return
+		}
+
+		contenttype := w.Header().Get("Content-Type")
+		if len(contenttype) == 0 {
+			contenttype = http.DetectContentType(aw.buf.Bytes())
+		}
+		if !strings.HasPrefix(contenttype, "text/html") {
+			return
+		}
+
+		issues, err := a11y.LintMarkup(aw.buf)
+		if err != nil {
+			m.logger.Error("Failed to lint the response for accessibility issues", "error", err, "path", r.URL.Path)
+			return
+		}
+		for _, issue := range issues {
+			m.logger.Warn(issue.Message, "rule", issue.Rule, "element", issue.Path, "path", r.URL.Path)
+		}
+		if aw.tail != nil && len(issues) > 0 {
+			cw := html.NewChunkWriter()
+			a11yoverlay(issues).Render(cw)
+			for _, chunk := range cw.Chunks() {
+				if err := html.Render(chunk, r.Context(), w); err != nil {
+					m.logger.Error("Failed to render the accessibility overlay", "error", err, "path", r.URL.Path)
+					return
+				}
+			}
+		}
+	})
+}
+
+func NewA11y(logger *slog.Logger, overlay bool) *A11y {
+	return &A11y{logger: logger, overlay: overlay}
+}
+
+// a11yoverlay lists the issues in a dialog, the dialog has no styles of its own so that
+// the content security policy of the page does not block it.
+func a11yoverlay(issues []a11y.Issue) html.Node {
+	items := []html.Item{}
+	for _, issue := range issues {
+		items = append(items, html.Li(
+			html.Code(html.Text(issue.Path)),
+			html.Text(" "+issue.Message+" ("+issue.Rule+")"),
+		))
+	}
+
+	return html.Dialog(
+		html.ID("pacis-a11y"),
+		html.Attr("open", ""),
+		html.Aria("labelledby", "pacis-a11y-title"),
+		html.H2(html.ID("pacis-a11y-title"), html.Text("Accessibility issues")),
+		html.Ul(items...),
+		html.Form(html.Method("dialog"), html.Button(html.Text("Close"))),
+	)
+}
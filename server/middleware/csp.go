@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canpacis/pacis/html"
+)
+
+// Source expressions of Content-Security-Policy directives,
+// see https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Headers/Content-Security-Policy#source_values
+const (
+	CSPSelf           = "'self'"
+	CSPNone           = "'none'"
+	CSPUnsafeInline   = "'unsafe-inline'"
+	CSPUnsafeEval     = "'unsafe-eval'"
+	CSPStrictDynamic  = "'strict-dynamic'"
+	CSPReportSample   = "'report-sample'"
+	CSPWasmUnsafeEval = "'wasm-unsafe-eval'"
+	CSPData           = "data:"
+	CSPBlob           = "blob:"
+	CSPHTTPS          = "https:"
+)
+
+type cspdirective struct {
+	name    string
+	sources []string
+}
+
+/*
+CSPPolicy builds the value of a Content-Security-Policy header. Directives are written in
+the order they are first added. The nonce of a request is added to the script-src and
+style-src directives, or to default-src if they are not set.
+
+Usage:
+
+	policy := middleware.NewCSPPolicy().
+		DefaultSrc(middleware.CSPSelf).
+		ScriptSrc(middleware.CSPSelf, middleware.CSPUnsafeEval).
+		ImgSrc(middleware.CSPSelf, middleware.CSPData).
+		ObjectSrc(middleware.CSPNone)
+
+https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Headers/Content-Security-Policy
+*/
+type CSPPolicy struct {
+	directives []cspdirective
+}
+
+// Add appends the sources to the given directive and returns the policy back.
+// Directives without sources (e.g. upgrade-insecure-requests) are written without a value.
+func (p *CSPPolicy) Add(directive string, sources ...string) *CSPPolicy {
+	for i := range p.directives {
+		if p.directives[i].name == directive {
+			for _, source := range sources {
+				if !slices.Contains(p.directives[i].sources, source) {
+					p.directives[i].sources = append(p.directives[i].sources, source)
+				}
+			}
+			return p
+		}
+	}
+	p.directives = append(p.directives, cspdirective{name: directive, sources: slices.Clone(sources)})
+	return p
+}
+
+// Sets the fallback sources for the other fetch directives.
+func (p *CSPPolicy) DefaultSrc(sources ...string) *CSPPolicy {
+	return p.Add("default-src", sources...)
+}
+
+// Sets the valid sources of scripts.
+func (p *CSPPolicy) ScriptSrc(sources ...string) *CSPPolicy {
+	return p.Add("script-src", sources...)
+}
+
+// Sets the valid sources of stylesheets.
+func (p *CSPPolicy) StyleSrc(sources ...string) *CSPPolicy {
+	return p.Add("style-src", sources...)
+}
+
+// Sets the valid sources of images and favicons.
+func (p *CSPPolicy) ImgSrc(sources ...string) *CSPPolicy {
+	return p.Add("img-src", sources...)
+}
+
+// Sets the valid sources of fonts.
+func (p *CSPPolicy) FontSrc(sources ...string) *CSPPolicy {
+	return p.Add("font-src", sources...)
+}
+
+// Restricts the URLs that can be loaded with scripts, e.g. fetch and WebSocket.
+func (p *CSPPolicy) ConnectSrc(sources ...string) *CSPPolicy {
+	return p.Add("connect-src", sources...)
+}
+
+// Sets the valid sources of audio and video.
+func (p *CSPPolicy) MediaSrc(sources ...string) *CSPPolicy {
+	return p.Add("media-src", sources...)
+}
+
+// Sets the valid sources of <object> and <embed> elements.
+func (p *CSPPolicy) ObjectSrc(sources ...string) *CSPPolicy {
+	return p.Add("object-src", sources...)
+}
+
+// Sets the valid sources of nested browsing contexts, e.g. <iframe>.
+func (p *CSPPolicy) FrameSrc(sources ...string) *CSPPolicy {
+	return p.Add("frame-src", sources...)
+}
+
+// Sets the valid parents that may embed the page.
+func (p *CSPPolicy) FrameAncestors(sources ...string) *CSPPolicy {
+	return p.Add("frame-ancestors", sources...)
+}
+
+// Restricts the URLs that can be used in the <base> element.
+func (p *CSPPolicy) BaseURI(sources ...string) *CSPPolicy {
+	return p.Add("base-uri", sources...)
+}
+
+// Restricts the URLs that can be used as the targets of form submissions.
+func (p *CSPPolicy) FormAction(sources ...string) *CSPPolicy {
+	return p.Add("form-action", sources...)
+}
+
+// Instructs the browsers to upgrade the insecure URLs of the page to HTTPS.
+func (p *CSPPolicy) UpgradeInsecureRequests() *CSPPolicy {
+	return p.Add("upgrade-insecure-requests")
+}
+
+// Sets the reporting endpoint, defined with the Reporting-Endpoints header, that violations are sent to.
+func (p *CSPPolicy) ReportTo(endpoint string) *CSPPolicy {
+	return p.Add("report-to", endpoint)
+}
+
+// String returns the header value of the policy with the given nonce, an empty nonce is omitted.
+func (p *CSPPolicy) String(nonce string) string {
+	noncesrc := []string{}
+	if len(nonce) > 0 {
+		noncesrc = append(noncesrc, "'nonce-"+nonce+"'")
+	}
+	hasscript := p.has("script-src")
+	hasstyle := p.has("style-src")
+
+	directives := make([]string, 0, len(p.directives))
+	for _, directive := range p.directives {
+		sources := directive.sources
+		switch directive.name {
+		case "script-src", "style-src":
+			sources = append(slices.Clone(sources), noncesrc...)
+		case "default-src":
+			if !hasscript || !hasstyle {
+				sources = append(slices.Clone(sources), noncesrc...)
+			}
+		}
+		directives = append(directives, strings.Join(append([]string{directive.name}, sources...), " "))
+	}
+	return strings.Join(directives, "; ")
+}
+
+func (p *CSPPolicy) has(directive string) bool {
+	return slices.ContainsFunc(p.directives, func(d cspdirective) bool { return d.name == directive })
+}
+
+// NewCSPPolicy creates an empty policy.
+func NewCSPPolicy() *CSPPolicy {
+	return &CSPPolicy{}
+}
+
+// DefaultCSPPolicy creates a policy that allows resources of the same origin and inline
+// scripts and styles with the nonce of the request.
+//
+// It allows 'unsafe-eval' in script-src because the x package renders Alpine directives and
+// the standard Alpine build evaluates their expressions with the Function constructor, so
+// no x-data or x-on would work without it. Apps that do not use Alpine, or use its CSP
+// build, should drop it by building their own policy with NewCSPPolicy.
+func DefaultCSPPolicy() *CSPPolicy {
+	return NewCSPPolicy().
+		DefaultSrc(CSPSelf).
+		ScriptSrc(CSPSelf, CSPUnsafeEval).
+		StyleSrc(CSPSelf).
+		ImgSrc(CSPSelf, CSPData).
+		ObjectSrc(CSPNone).
+		BaseURI(CSPSelf)
+}
+
+// CSP is a middleware that generates a random nonce for every request and sets the
+// Content-Security-Policy header with it. The nonce is stored in the request context, so
+// every html.Script and html.Style element rendered within the request gets a nonce
+// attribute, see html.WithNonce. The server only builds the pages with nonces when they are
+// registered after the middleware is used, either with Server.Use or as a page middleware.
+// In development the policy should also allow the Vite dev server in the script-src and
+// connect-src directives.
+type CSP struct {
+	Policy *CSPPolicy
+	// Sets the Content-Security-Policy-Report-Only header instead, so violations are reported but not enforced
+	ReportOnly bool
+}
+
+func (*CSP) Name() string {
+	return "CSP"
+}
+
+func (m *CSP) Apply(h http.Handler) http.Handler {
+	header := "Content-Security-Policy"
+	if m.ReportOnly {
+		header = "Content-Security-Policy-Report-Only"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce := NewNonce()
+		w.Header().Set(header, m.Policy.String(nonce))
+		h.ServeHTTP(w, r.WithContext(html.WithNonce(r.Context(), nonce)))
+	})
+}
+
+func NewCSP(policy *CSPPolicy) *CSP {
+	return &CSP{Policy: policy}
+}
+
+// NewNonce returns a base64 encoded random value of 128 bits to be used as a nonce.
+func NewNonce() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// GetNonce retrieves the nonce of the request from the provided context.
+// It returns an empty string if the CSP middleware is not registered.
+func GetNonce(ctx context.Context) string {
+	return html.Nonce(ctx)
+}
@@ -1,5 +1,5 @@
 // Package middleware provides HTTP middleware utilities for handling
-// color scheme preferences, locale selection, caching, logging, and gzip compression.
+// color scheme preferences, locale selection, caching, logging, gzip compression, and content security policies.
 //
 // The package includes the following middleware:
 //
@@ -9,6 +9,8 @@
 //   - Cache: Sets Cache-Control headers for HTTP responses to enable client-side caching.
 //   - Logger: Logs HTTP requests with method, status, path, remote address, user agent, and duration.
 //   - Gzip: Provides gzip compression for HTTP responses.
+//   - CSP: Sets the Content-Security-Policy header with a per-request nonce for inline scripts and styles.
+//   - A11y: Logs the accessibility issues of HTML responses in development and lists them in an overlay.
 //
 // Helper functions are provided to retrieve the color scheme, localizer, and locale from the request context.
 package middleware
@@ -33,11 +33,14 @@ import (
 	"os"
 	"os/signal"
 	"path"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/canpacis/pacis/html"
 	"github.com/canpacis/pacis/internal"
+	"github.com/canpacis/pacis/server/metadata"
 	"github.com/canpacis/pacis/server/middleware"
 )
 
@@ -53,13 +56,22 @@ const (
 )
 
 // Options holds the configuration settings for the server, including environment,
-// port, development server URL, logger, and HTTP request multiplexer.
+// port, development server URL, logger, HTTP request multiplexer and the render mode
+// of the pages.
 type Options struct {
-	Env       Environment
-	Port      string
-	DevServer *url.URL
-	Logger    *slog.Logger
-	Mux       *http.ServeMux
+	Env        Environment
+	Port       string
+	DevServer  *url.URL
+	Logger     *slog.Logger
+	Mux        *http.ServeMux
+	RenderMode html.RenderMode
+	// Whether the content models of the pages are validated when they are built, see
+	// html.Validate. Violations are logged as warnings in the development environment and
+	// returned by Server.Validate, e.g. to fail a test.
+	//
+	// Components are not rendered when the pages are built, so the nodes they return are
+	// NOT validated. Only the static parts of the pages and their layouts are checked.
+	Validate bool
 }
 
 type entry struct {
@@ -78,6 +90,17 @@ type Server struct {
 	manifest    manifest
 	options     *Options
 	notfound    http.Handler
+	// Pages registered with HandlePage and the build directory, see Export
+	pages  []exportpage
+	static fs.FS
+	// Options of the sitemap, set with HandleSitemap
+	sitemap *SitemapOptions
+	// Alternate links of the feeds registered with HandleFeed
+	feeds []metadata.Alternate
+	// Content model violations of the pages, see Options.Validate
+	violations error
+	// Errors of the pages that failed to build, see Err
+	errs error
 }
 
 // Adds middleware(s) to the application's middleware stack.
@@ -105,6 +128,7 @@ func clean(pattern string, prefix string) string {
 
 func (s *Server) HandlePage(pattern string, page Page, layout Layout, middlewares ...middleware.Middleware) {
 	s.Handle(clean(pattern, "GET"), PageHandler(s, page, layout, middlewares...))
+	s.pages = append(s.pages, exportpage{pattern: clean(pattern, ""), page: page})
 
 	actioner, ok := page.(interface{ Actions() map[string]ActionFunc })
 	if !ok {
@@ -120,6 +144,7 @@ func (s *Server) SetBuildDir(name string, dir fs.FS, vite fs.FS) error {
 		return err
 	}
 
+	s.static = static
 	var handler http.Handler = internal.NewFileServer(static, s.notfound)
 	s.Handle("GET /{path}", middleware.NewCache(time.Hour*24*365).Apply(handler))
 
@@ -157,12 +182,78 @@ func (s *Server) RegisterDevHandlers() {
 }
 
 func (s *Server) SetNotFoundPage(page Page, layout Layout) {
-	s.notfound = handler(s, page, layout, true)
+	s.notfound = handler(s, page, layout, true, s.csp(nil))
 	for i := len(s.middlewares) - 1; i >= 0; i-- {
 		s.notfound = s.middlewares[i].Apply(s.notfound)
 	}
 }
 
+// csp reports whether the server or the given page middlewares include the CSP middleware,
+// the pages registered afterwards are built with nonces.
+func (s *Server) csp(middlewares []middleware.Middleware) bool {
+	for _, m := range append(slices.Clone(s.middlewares), middlewares...) {
+		if _, ok := m.(*middleware.CSP); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records the error of a page that failed to build, the page responds with 500 Internal
+// Server Error, see Err.
+func (s *Server) fail(page Page, err error) {
+	s.errs = errors.Join(s.errs, fmt.Errorf("%T: %w", page, err))
+	s.options.Logger.Error("Failed to statically render page", "page", fmt.Sprintf("%T", page), "error", err)
+}
+
+/*
+Err returns the errors of the pages registered so far that failed to build, e.g. pages with
+invalid static structured data. These pages respond with 500 Internal Server Error, apps
+that would rather not start with them check Err after registering their pages.
+
+Usage:
+
+	app.Register(srv)
+	if err := srv.Err(); err != nil {
+		log.Fatal(err)
+	}
+	srv.Serve()
+*/
+func (s *Server) Err() error {
+	return s.errs
+}
+
+// report logs the errors that error boundaries catch while the pages are built.
+func (s *Server) report(err error) {
+	s.options.Logger.Error("Error boundary caught an error", "error", err)
+}
+
+// validate records the content model violations of a page, see Options.Validate.
+func (s *Server) validate(page Page, err error) error {
+	s.violations = errors.Join(s.violations, fmt.Errorf("%T: %w", page, err))
+	if s.options.Env == Dev {
+		s.options.Logger.Warn("Page has an invalid content model", "page", fmt.Sprintf("%T", page), "error", err)
+	}
+	return nil
+}
+
+/*
+Validate returns the content model violations of the pages registered so far, it is nil
+unless the Validate option is set. Nodes returned by components are not validated, see
+html.Validate.
+
+Usage:
+
+	func TestPages(t *testing.T) {
+		srv := server.New(&server.Options{Validate: true, ...})
+		app.Register(srv)
+		assert.NoError(t, srv.Validate())
+	}
+*/
+func (s *Server) Validate() error {
+	return s.violations
+}
+
 // Asset returns the URL or path for a given asset name based on the current application context.
 // In development mode, it constructs the asset URL using the development server and webfiles path.
 // In production mode, it retrieves the asset entry from the application's entries map.
@@ -188,6 +279,7 @@ func (s *Server) Asset(name string) string {
 	return "/" + entry.File
 }
 
+// Serve starts the server and blocks until it receives an interrupt signal.
 func (s *Server) Serve() {
 	server := &http.Server{
 		Addr:              s.options.Port,
@@ -223,7 +315,8 @@ func (s *Server) Serve() {
 // New creates and returns a new Server instance using the provided Options.
 // It sets default values for any missing options, including the HTTP mux, development server URL,
 // environment, and logger. The function also attaches default middleware for logging and recovery,
-// and sets the default "not found" page handler.
+// and in the development environment for reporting accessibility issues, and sets the default
+// "not found" page handler.
 //
 // Parameters:
 //
@@ -252,6 +345,9 @@ func New(options *Options) *Server {
 	}
 
 	s.Use(middleware.NewLogger(s.options.Logger), middleware.NewRecover(s.options.Logger, nil))
+	if s.options.Env == Dev {
+		s.Use(middleware.NewA11y(s.options.Logger, true))
+	}
 	s.SetNotFoundPage(internal.NotFoundPage, DefaultLayout)
 	return s
 }
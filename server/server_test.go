@@ -3,12 +3,17 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/canpacis/pacis/html"
 	"github.com/canpacis/pacis/internal"
 	"github.com/canpacis/pacis/server"
+	"github.com/canpacis/pacis/server/metadata"
+	"github.com/canpacis/pacis/server/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -89,3 +94,164 @@ func TestAsync(t *testing.T) {
 	server.Async(component, nil)(ctx)
 	assert.Equal(1, len(ctx.AsyncChunks))
 }
+
+func TestStaticRendererError(t *testing.T) {
+	assert := assert.New(t)
+
+	renderer := server.NewStaticRenderer()
+	assert.NoError(renderer.Build(html.Div(html.Component(func(ctx context.Context) html.Node {
+		return html.JSON(make(chan int))
+	}))))
+	assert.Error(renderer.Render(context.Background(), new(bytes.Buffer)))
+
+	assert.Error(server.NewStaticRenderer().Build(html.Div(html.JSON(make(chan int)), html.P(html.Text("After")))))
+
+	reported := []error{}
+	renderer = server.NewStaticRenderer().WithErrorReporter(func(err error) {
+		reported = append(reported, err)
+	})
+	assert.NoError(renderer.Build(html.ErrorBoundary(html.JSON(make(chan int)), func(error) html.Node {
+		return html.Text("Unavailable")
+	})))
+	assert.Len(reported, 1)
+	buf := new(bytes.Buffer)
+	assert.NoError(renderer.Render(context.Background(), buf))
+	assert.Equal("Unavailable", buf.String())
+}
+
+func TestStaticRendererValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	node := html.P(html.Div(html.Text("Hello")))
+	assert.NoError(server.NewStaticRenderer().Build(node))
+
+	var reported error
+	assert.NoError(server.NewStaticRenderer().WithValidator(func(err error) error {
+		reported = err
+		return nil
+	}).Build(node))
+	assert.ErrorContains(reported, "p > div: <div> is not allowed in <p>")
+
+	err := server.NewStaticRenderer().WithValidator(func(err error) error { return err }).Build(node)
+	var cmerr *html.ContentModelError
+	assert.ErrorAs(err, &cmerr)
+}
+
+func TestServerValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	options := &server.Options{
+		Env:      server.Prod,
+		Mux:      http.NewServeMux(),
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Validate: true,
+	}
+	srv := server.New(options)
+	srv.HandlePage("/{$}", server.PageFunc(func() html.Node { return html.Main(html.P(html.Text("Hello"))) }), server.DefaultLayout)
+	assert.NoError(srv.Validate())
+
+	srv.HandlePage("/invalid", server.PageFunc(func() html.Node { return html.P(html.Div()) }), server.DefaultLayout)
+	var cmerr *html.ContentModelError
+	assert.ErrorAs(srv.Validate(), &cmerr)
+	assert.ErrorContains(srv.Validate(), "p > div: <div> is not allowed in <p>")
+
+	// The page is still served
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/invalid", nil))
+	assert.Equal(http.StatusOK, w.Code)
+
+	options.Validate = false
+	options.Mux = http.NewServeMux()
+	srv = server.New(options)
+	srv.HandlePage("/invalid", server.PageFunc(func() html.Node { return html.P(html.Div()) }), server.DefaultLayout)
+	assert.NoError(srv.Validate())
+}
+
+func TestPageNonce(t *testing.T) {
+	assert := assert.New(t)
+
+	page := server.PageFunc(func() html.Node { return html.Main(html.Script(html.RawUnsafe("init()"))) })
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	srv.HandlePage("/{$}", page, nil)
+	srv.HandlePage("/csp", page, nil, middleware.NewCSP(middleware.DefaultCSPPolicy()))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	assert.Equal(`<main><script>init()</script></main>`, get("/").Body.String())
+
+	w := get("/csp")
+	assert.Regexp(`script-src 'self' 'unsafe-eval' 'nonce-[^']+'`, w.Header().Get("Content-Security-Policy"))
+	assert.Regexp(`<main><script nonce="[^"]+">init\(\)</script></main>`, w.Body.String())
+}
+
+type articlepage struct{}
+
+func (articlepage) Page() html.Node { return html.Main() }
+
+func (articlepage) Metadata() *metadata.Metadata {
+	return &metadata.Metadata{Title: "Article", StructuredData: []metadata.StructuredData{&metadata.Article{}}}
+}
+
+type dynamicarticlepage struct{}
+
+func (dynamicarticlepage) Page() html.Node { return html.Main() }
+
+func (dynamicarticlepage) Metadata(context.Context) *metadata.Metadata {
+	return articlepage{}.Metadata()
+}
+
+func TestInvalidStructuredData(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Static metadata is rendered when the page is registered, the error is collected
+	srv.HandlePage("/article", articlepage{}, server.DefaultLayout)
+	assert.ErrorContains(srv.Err(), "server_test.articlepage: ")
+	assert.ErrorContains(srv.Err(), "metadata: Article requires the headline property")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/article", nil))
+	assert.Equal(http.StatusInternalServerError, w.Code)
+
+	// Dynamic metadata fails the request
+	srv.HandlePage("/{$}", dynamicarticlepage{}, server.DefaultLayout)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(http.StatusInternalServerError, w.Code)
+	assert.NotContains(w.Body.String(), "application/ld+json")
+}
+
+type provided string
+
+func TestAsyncProvide(t *testing.T) {
+	assert := assert.New(t)
+
+	rw := NewResponseWriter()
+	r, err := http.NewRequest("GET", "/", nil)
+	assert.NoError(err)
+	ctx := internal.NewContext(rw, r)
+
+	component := func(ctx context.Context) html.Node {
+		value, _ := html.Use[provided](ctx)
+		return html.Text(value)
+	}
+	renderer := server.NewStaticRenderer()
+	assert.NoError(renderer.Build(html.Provide(provided("value"), server.Async(component, nil))))
+	assert.NoError(renderer.Render(ctx, new(bytes.Buffer)))
+
+	// The server context is found through the context of the provider
+	assert.Equal(1, len(ctx.AsyncChunks))
+	chunk := ctx.AsyncChunks[0]
+	assert.Equal(html.Text("value"), chunk.Component(chunk.Context))
+}
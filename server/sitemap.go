@@ -0,0 +1,345 @@
+package server
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canpacis/pacis/server/metadata"
+)
+
+// ChangeFreq is the frequency a page is likely to change at, see SitemapEntry.
+type ChangeFreq string
+
+const (
+	ChangeAlways  = ChangeFreq("always")
+	ChangeHourly  = ChangeFreq("hourly")
+	ChangeDaily   = ChangeFreq("daily")
+	ChangeWeekly  = ChangeFreq("weekly")
+	ChangeMonthly = ChangeFreq("monthly")
+	ChangeYearly  = ChangeFreq("yearly")
+	ChangeNever   = ChangeFreq("never")
+)
+
+// SitemapEntry is a URL of the sitemap, see https://www.sitemaps.org/protocol.html
+type SitemapEntry struct {
+	// Path of the page, e.g. "/blog/hello-world". Pages that return their own entries may
+	// set the Params of their route pattern instead.
+	Path   string
+	Params map[string]string
+	// Time the page was last modified, omitted if zero
+	LastMod    time.Time
+	ChangeFreq ChangeFreq
+	// Priority of the page relative to the other pages of the site between 0 and 1, omitted if zero
+	Priority float64
+	// Localized versions of the page, only the alternates with a HrefLang are written
+	Alternates []metadata.Alternate
+}
+
+// SitemapOptions configures the sitemap and the robots.txt served by the server.
+type SitemapOptions struct {
+	// Base URL of the site the absolute URLs are built with, e.g. https://example.com.
+	// The scheme and the host of the request are used if it is nil.
+	BaseURL *url.URL
+	// Default values of the entries that do not set them
+	ChangeFreq ChangeFreq
+	Priority   float64
+	// Maximum number of URLs in a sitemap, a sitemap index is served above it. Defaults to 50000.
+	MaxURLs int
+	// Entries returns additional entries that are not registered with HandlePage, e.g. pages served by other handlers
+	Entries func(context.Context) ([]SitemapEntry, error)
+}
+
+type xmlalternate struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type xmlurl struct {
+	Loc        string         `xml:"loc"`
+	LastMod    string         `xml:"lastmod,omitempty"`
+	ChangeFreq ChangeFreq     `xml:"changefreq,omitempty"`
+	Priority   string         `xml:"priority,omitempty"`
+	Alternates []xmlalternate `xml:"xhtml:link"`
+}
+
+type xmlurlset struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	XHTML   string   `xml:"xmlns:xhtml,attr"`
+	URLs    []xmlurl `xml:"url"`
+}
+
+type xmlsitemap struct {
+	Loc string `xml:"loc"`
+}
+
+type xmlsitemapindex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlsitemap `xml:"sitemap"`
+}
+
+const sitemapns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// staticmetadata returns the metadata of pages that do not depend on the request.
+func staticmetadata(page Page) *metadata.Metadata {
+	meta, ok := page.(interface{ Metadata() *metadata.Metadata })
+	if !ok {
+		return nil
+	}
+	return meta.Metadata()
+}
+
+// noindex reports whether the page asks not to be indexed.
+func noindex(meta *metadata.Metadata) bool {
+	return meta != nil && meta.Robots != nil && !meta.Robots.Index
+}
+
+// disallowed reports whether the page asks not to be crawled.
+func disallowed(meta *metadata.Metadata) bool {
+	return meta != nil && meta.Robots != nil && meta.Robots.Disallow
+}
+
+// baseurl returns the base URL of the absolute URLs of the sitemap.
+func (o *SitemapOptions) baseurl(r *http.Request) *url.URL {
+	if o.BaseURL != nil {
+		return o.BaseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); len(proto) > 0 {
+		scheme = proto
+	}
+	return &url.URL{Scheme: scheme, Host: r.Host}
+}
+
+// entries collects the sitemap entries of the registered pages and the options.
+func (s *Server) entries(ctx context.Context, options *SitemapOptions) ([]SitemapEntry, error) {
+	entries := []SitemapEntry{}
+	for i := range s.pages {
+		page := &s.pages[i]
+		meta := staticmetadata(page.page)
+		if noindex(meta) || disallowed(meta) {
+			continue
+		}
+
+		var pageentries []SitemapEntry
+		if sitemapper, ok := page.page.(interface {
+			Sitemap(context.Context) []SitemapEntry
+		}); ok {
+			pageentries = sitemapper.Sitemap(ctx)
+		} else {
+			paths, err := page.paths()
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range paths {
+				pageentries = append(pageentries, SitemapEntry{Path: path})
+			}
+		}
+
+		for _, entry := range pageentries {
+			if len(entry.Path) == 0 {
+				expanded, err := page.expand(entry.Params)
+				if err != nil {
+					return nil, err
+				}
+				entry.Path = expanded
+			}
+			if meta != nil {
+				if len(meta.Canonical) > 0 && !wildcard.MatchString(strings.ReplaceAll(page.route(), "{$}", "")) {
+					entry.Path = meta.Canonical
+				}
+				entry.Alternates = append(entry.Alternates, meta.Alternates...)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	if options.Entries != nil {
+		extra, err := options.Entries(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, extra...)
+	}
+	return entries, nil
+}
+
+func (o *SitemapOptions) url(base *url.URL, entry SitemapEntry) xmlurl {
+	resolve := func(ref string) string {
+		parsed, err := url.Parse(ref)
+		if err != nil {
+			return ref
+		}
+		return base.ResolveReference(parsed).String()
+	}
+
+	u := xmlurl{Loc: resolve(entry.Path), ChangeFreq: entry.ChangeFreq}
+	if len(u.ChangeFreq) == 0 {
+		u.ChangeFreq = o.ChangeFreq
+	}
+	if !entry.LastMod.IsZero() {
+		u.LastMod = entry.LastMod.UTC().Format(time.RFC3339)
+	}
+	priority := entry.Priority
+	if priority == 0 {
+		priority = o.Priority
+	}
+	if priority > 0 {
+		u.Priority = strconv.FormatFloat(min(priority, 1), 'f', 1, 64)
+	}
+	for _, alternate := range entry.Alternates {
+		if len(alternate.HrefLang) > 0 && len(alternate.Href) > 0 {
+			u.Alternates = append(u.Alternates, xmlalternate{Rel: "alternate", HrefLang: alternate.HrefLang, Href: resolve(alternate.Href)})
+		}
+	}
+	return u
+}
+
+func writexml(w http.ResponseWriter, v any) error {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+/*
+HandleSitemap serves a sitemap of the pages registered with HandlePage at /sitemap.xml,
+see https://www.sitemaps.org/protocol.html
+
+Pages whose static metadata sets Robots.Index to false or Robots.Disallow are left out, the
+canonical URL and
+the alternates with a HrefLang of the metadata are used for the other pages. Pages with path
+parameters are listed for every path returned by their `Paths() []map[string]string` method.
+Pages can also return their entries, e.g. to set the last modification times, with a
+`Sitemap(context.Context) []server.SitemapEntry` method:
+
+	func (*Post) Sitemap(ctx context.Context) []server.SitemapEntry {
+		entries := []server.SitemapEntry{}
+		for _, post := range posts {
+			entries = append(entries, server.SitemapEntry{Params: map[string]string{"slug": post.Slug}, LastMod: post.UpdatedAt})
+		}
+		return entries
+	}
+
+Sitemaps with more URLs than options.MaxURLs are split into /sitemap/1.xml, /sitemap/2.xml, ...
+which are listed by a sitemap index at /sitemap.xml. Pages must be registered before the
+sitemap is served.
+*/
+func (s *Server) HandleSitemap(options *SitemapOptions) {
+	if options == nil {
+		options = &SitemapOptions{}
+	}
+	if options.MaxURLs <= 0 {
+		options.MaxURLs = 50000
+	}
+	s.sitemap = options
+
+	serve := func(w http.ResponseWriter, r *http.Request, part int) {
+		entries, err := s.entries(r.Context(), options)
+		if err != nil {
+			s.options.Logger.Error("Failed to generate sitemap", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		base := options.baseurl(r)
+		parts := (len(entries) + options.MaxURLs - 1) / options.MaxURLs
+
+		if part == 0 && parts > 1 {
+			index := xmlsitemapindex{XMLNS: sitemapns}
+			for i := 1; i <= parts; i++ {
+				index.Sitemaps = append(index.Sitemaps, xmlsitemap{Loc: base.JoinPath("sitemap", fmt.Sprintf("%d.xml", i)).String()})
+			}
+			writexml(w, index)
+			return
+		}
+		if part > max(parts, 1) || (part > 0 && parts <= 1) {
+			s.notfound.ServeHTTP(w, r)
+			return
+		}
+
+		start := max(part-1, 0) * options.MaxURLs
+		end := min(start+options.MaxURLs, len(entries))
+		set := xmlurlset{XMLNS: sitemapns, XHTML: "http://www.w3.org/1999/xhtml", URLs: []xmlurl{}}
+		for _, entry := range entries[start:end] {
+			set.URLs = append(set.URLs, options.url(base, entry))
+		}
+		writexml(w, set)
+	}
+
+	s.Handle("GET /sitemap.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, 0)
+	}))
+	s.Handle("GET /sitemap/{file}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		part, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("file"), ".xml"))
+		if err != nil || part < 1 || !strings.HasSuffix(r.PathValue("file"), ".xml") {
+			s.notfound.ServeHTTP(w, r)
+			return
+		}
+		serve(w, r, part)
+	}))
+}
+
+/*
+HandleRobots serves a robots.txt at /robots.txt derived from the Robots metadata of the pages
+registered with HandlePage. Every user agent may crawl the site except the pages whose static
+metadata sets Robots.Disallow, their route patterns are disallowed with the path parameters
+replaced by wildcards, e.g. "/admin/{id}" becomes "Disallow: /admin/*". The sitemap is
+referenced if it is served with HandleSitemap.
+
+Pages whose static metadata only sets Robots.Index to false are left out of the sitemap but
+they are not disallowed, since crawlers must be able to fetch a page to see its noindex
+directive. Pages with metadata that depends on the request are always allowed.
+
+Usage:
+
+	func (*Admin) Metadata() *metadata.Metadata {
+		return &metadata.Metadata{Robots: &metadata.Robots{Disallow: true}}
+	}
+
+	srv.HandlePage("/admin/{id}", &Admin{}, Layout)
+	srv.HandleRobots()
+*/
+func (s *Server) HandleRobots() {
+	s.Handle("GET /robots.txt", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := new(strings.Builder)
+		b.WriteString("User-agent: *\n")
+
+		disallow := []string{}
+		for i := range s.pages {
+			page := &s.pages[i]
+			if !disallowed(staticmetadata(page.page)) {
+				continue
+			}
+			pattern := strings.ReplaceAll(page.route(), "{$}", "$")
+			pattern = wildcard.ReplaceAllString(pattern, "*")
+			if !slices.Contains(disallow, pattern) {
+				disallow = append(disallow, pattern)
+			}
+		}
+		for _, pattern := range disallow {
+			fmt.Fprintf(b, "Disallow: %s\n", pattern)
+		}
+		if len(disallow) == 0 {
+			b.WriteString("Allow: /\n")
+		}
+
+		if s.sitemap != nil {
+			fmt.Fprintf(b, "\nSitemap: %s\n", s.sitemap.baseurl(r).JoinPath("sitemap.xml").String())
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(b.String()))
+	}))
+}
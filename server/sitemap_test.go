@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/canpacis/pacis/html"
+	"github.com/canpacis/pacis/server"
+	"github.com/canpacis/pacis/server/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+type localized struct{}
+
+func (*localized) Page() html.Node { return html.Fragment() }
+
+func (*localized) Metadata() *metadata.Metadata {
+	return &metadata.Metadata{
+		Canonical:  "/about",
+		Alternates: []metadata.Alternate{{HrefLang: "tr", Href: "/tr/about"}, {Type: "application/rss+xml", Href: "/feed"}},
+	}
+}
+
+type private struct{}
+
+func (*private) Page() html.Node { return html.Fragment() }
+
+func (*private) Metadata() *metadata.Metadata {
+	return &metadata.Metadata{Robots: &metadata.Robots{Index: false}}
+}
+
+type admin struct{}
+
+func (*admin) Page() html.Node { return html.Fragment() }
+
+func (*admin) Metadata() *metadata.Metadata {
+	return &metadata.Metadata{Robots: &metadata.Robots{Disallow: true}}
+}
+
+type posts struct{}
+
+func (*posts) Page() html.Node { return html.Fragment() }
+
+func (*posts) Metadata() *metadata.Metadata { return &metadata.Metadata{} }
+
+func (*posts) Sitemap(context.Context) []server.SitemapEntry {
+	return []server.SitemapEntry{
+		{Params: map[string]string{"slug": "hello"}, LastMod: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Priority: 0.8},
+	}
+}
+
+func TestSitemap(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := server.New(&server.Options{
+		Env:    server.Prod,
+		Mux:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	srv.HandlePage("/{$}", server.PageFunc(func() html.Node { return html.Fragment() }), nil)
+	srv.HandlePage("/about", &localized{}, nil)
+	srv.HandlePage("/drafts", &private{}, nil)
+	srv.HandlePage("/admin/{id}", &admin{}, nil)
+	srv.HandlePage("/blog/{slug}", &posts{}, nil)
+	base, _ := url.Parse("https://example.com")
+	options := &server.SitemapOptions{BaseURL: base, ChangeFreq: server.ChangeWeekly}
+	srv.HandleSitemap(options)
+	srv.HandleRobots()
+
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(http.StatusOK, w.Code, path)
+		return w.Body.String()
+	}
+
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">`+
+		`<url><loc>https://example.com/</loc><changefreq>weekly</changefreq></url>`+
+		`<url><loc>https://example.com/about</loc><changefreq>weekly</changefreq><xhtml:link rel="alternate" hreflang="tr" href="https://example.com/tr/about"></xhtml:link></url>`+
+		`<url><loc>https://example.com/blog/hello</loc><lastmod>2025-01-02T00:00:00Z</lastmod><changefreq>weekly</changefreq><priority>0.8</priority></url>`+
+		`</urlset>`, get("/sitemap.xml"))
+
+	assert.Equal("User-agent: *\nDisallow: /admin/*\n\nSitemap: https://example.com/sitemap.xml\n", get("/robots.txt"))
+
+	// Pages that are not indexed stay crawlable unless they disallow it
+	plain := server.New(&server.Options{Env: server.Prod, Mux: http.NewServeMux(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	plain.HandlePage("/drafts", &private{}, nil)
+	plain.HandleRobots()
+	w := httptest.NewRecorder()
+	plain.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+	assert.Equal("User-agent: *\nAllow: /\n", w.Body.String())
+
+	options.MaxURLs = 2
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+		`<sitemap><loc>https://example.com/sitemap/1.xml</loc></sitemap>`+
+		`<sitemap><loc>https://example.com/sitemap/2.xml</loc></sitemap>`+
+		`</sitemapindex>`, get("/sitemap.xml"))
+	assert.Contains(get("/sitemap/2.xml"), `<loc>https://example.com/blog/hello</loc>`)
+	assert.NotContains(get("/sitemap/2.xml"), `<loc>https://example.com/about</loc>`)
+}